@@ -0,0 +1,132 @@
+package checkers
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Ballot is a three-move opening: the first two moves of the side that starts
+// and the first of the other, in square numbers such as "11-15". Under the
+// three-move restriction each game of a match starts from a ballot drawn from
+// a deck, and the players then swap colours and play it again.
+type Ballot [3]string
+
+func (bl Ballot) String() string { return strings.Join(bl[:], " ") }
+
+// ParseBallot reads a ballot written as three moves separated by spaces,
+// checking that they can be played from the start.
+func ParseBallot(s string) (Ballot, error) {
+	var bl Ballot
+	fields := strings.Fields(s)
+	if len(fields) != len(bl) {
+		return bl, fmt.Errorf("checkers: ballot %q is not three moves", s)
+	}
+	copy(bl[:], fields)
+	_, err := NewBoardFromBallot(bl)
+	return bl, err
+}
+
+// NewBoardFromBallot returns a board with the ballot's moves played from the
+// start, so the game's record begins with them.
+func NewBoardFromBallot(bl Ballot) (*Board, error) {
+	b := NewBoard()
+	for _, text := range bl {
+		m, err := b.parseMove(text)
+		if err != nil {
+			return nil, fmt.Errorf("checkers: ballot %s: %s is an %w in\n%s", bl, text, err, b.BitBoard)
+		}
+		m.MakeMove(b)
+		b.PlyCount++
+	}
+	return b, nil
+}
+
+// parseMove reads a move in square notation. A capture may be written with
+// "-" or "x", and a multiple jump by its first and last squares alone if that
+// is unambiguous.
+func (b *Board) parseMove(text string) (Move, error) {
+	var squares []int
+	for _, part := range strings.Split(strings.ReplaceAll(text, "x", "-"), "-") {
+		sq, err := strconv.Atoi(part)
+		if err != nil {
+			return Move{}, ErrIllegalMove
+		}
+		squares = append(squares, sq)
+	}
+	return b.FindMove(squares)
+}
+
+// AllThreeMoveOpenings returns every distinct three-move opening, in the order
+// the moves are generated, leaving out any that reaches the same position as
+// one before it. Some of them are lost for one side, so matches play from
+// a smaller deck; see BalancedDeck.
+func AllThreeMoveOpenings() []Ballot {
+	var deck []Ballot
+	seen := make(map[BitBoard]bool)
+	b := NewBoard()
+	for _, m1 := range b.GenerateAllMoves() {
+		b.Save()
+		m1.MakeMove(b)
+		for _, m2 := range b.GenerateAllMoves() {
+			b.Save()
+			m2.MakeMove(b)
+			for _, m3 := range b.GenerateAllMoves() {
+				b.Save()
+				m3.MakeMove(b)
+				if !seen[*b.BitBoard] {
+					seen[*b.BitBoard] = true
+					deck = append(deck, Ballot{m1.String(), m2.String(), m3.String()})
+				}
+				b.Load()
+			}
+			b.Load()
+		}
+		b.Load()
+	}
+	return deck
+}
+
+//go:generate go run ../cmd/checkers ballots ballots.txt
+//go:embed ballots.txt
+var balancedDeck string
+
+// BalancedDeck returns the deck of ballots for play: the three-move openings
+// less those a search finds clearly won for one side. It is read from
+// ballots.txt, which the ballots command writes one ballot to a line as for
+// ReadBallots.
+func BalancedDeck() []Ballot {
+	deck, err := ReadBallots(strings.NewReader(balancedDeck))
+	if err != nil {
+		panic("checkers: ballots.txt: " + err.Error())
+	}
+	return deck
+}
+
+// RandomBallot draws a ballot from deck.
+func RandomBallot(deck []Ballot, r *rand.Rand) Ballot {
+	return deck[r.Intn(len(deck))]
+}
+
+// ReadBallots reads a deck written one ballot to a line. Blank lines and
+// anything after a "#" are ignored.
+func ReadBallots(r io.Reader) ([]Ballot, error) {
+	var deck []Ballot
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		bl, err := ParseBallot(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		deck = append(deck, bl)
+	}
+	return deck, s.Err()
+}
@@ -0,0 +1,76 @@
+package checkers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAllThreeMoveOpenings(t *testing.T) {
+	deck := AllThreeMoveOpenings()
+	// 302 ways to play three moves reach 216 different positions
+	if len(deck) != 216 {
+		t.Fatalf("%d ballots, want 216", len(deck))
+	}
+	for _, bl := range deck {
+		parsed, err := ParseBallot(bl.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != bl {
+			t.Fatalf("parsed %s as %s", bl, parsed)
+		}
+	}
+}
+
+func TestBalancedDeck(t *testing.T) {
+	deck := BalancedDeck()
+	if len(deck) != 197 {
+		t.Fatalf("%d ballots, want 197", len(deck))
+	}
+	all := make(map[Ballot]bool)
+	for _, bl := range AllThreeMoveOpenings() {
+		all[bl] = true
+	}
+	in := make(map[Ballot]bool)
+	for _, bl := range deck {
+		if !all[bl] || in[bl] {
+			t.Fatalf("%s is repeated or not one of the three-move openings", bl)
+		}
+		in[bl] = true
+	}
+	for _, s := range []string{"11-15 23-19 8-11", "11-15 22-18 15x22", "11-15 24-20 8-11", "11-15 22-17 9-14"} {
+		if bl, _ := ParseBallot(s); !in[bl] {
+			t.Errorf("%s is not in the deck", s)
+		}
+	}
+	// 13-17 gives a man away for nothing
+	if bl, _ := ParseBallot("9-13 24-19 13-17"); in[bl] {
+		t.Errorf("%s is in the deck", bl)
+	}
+}
+
+func TestReadBallots(t *testing.T) {
+	deck, err := ReadBallots(strings.NewReader(`
+# Old fourteenth
+11-15 23-19 8-11
+9-13 22-18 10-15 # a comment
+11-15 22-18 15-22
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deck) != 3 || deck[2].String() != "11-15 22-18 15-22" {
+		t.Fatalf("read %v", deck)
+	}
+	b, err := NewBoardFromBallot(deck[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := b.LastMove(); !m.IsJump {
+		t.Fatalf("15-22 was played as %s", m)
+	}
+
+	if _, err := ReadBallots(strings.NewReader("11-15 23-19 9-13\n11-15 23-19 15-11\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("a ballot with an illegal move gave %v", err)
+	}
+}
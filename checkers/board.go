@@ -0,0 +1,234 @@
+// Package checkers implements the rules of English draughts on a bitboard.
+// It has no UI dependencies so it can be shared by the GUI, the bots and tests.
+package checkers
+
+const BoardSize = 8 // 8x8 grid
+
+type Spot struct {
+	X, Y int
+}
+
+// Board holds the current position together with the undo stack used by the search.
+type Board struct {
+	BitBoard   *BitBoard
+	bbStack    []undoState
+	PlyCount   int
+	NodeBudget int
+	DrawRules  DrawRules
+
+	history      []BitBoard // every position reached, used for repetition checks
+	moves        []Move     // moves[i] led from history[i] to history[i+1]
+	irreversible int        // index in history of the last position after a capture or man move
+	agreedDraw   bool
+}
+
+// undoState is what Save records so Load can put the board back exactly.
+type undoState struct {
+	bitBoard     BitBoard
+	historyLen   int
+	movesLen     int
+	irreversible int
+}
+
+type BitBoard struct {
+	Exists    uint64
+	Red       uint64
+	King      uint64
+	IsRedTurn bool
+	Hash      uint64 // Zobrist key, kept up to date by Set, Clear, SetRedTurn and MakeMove
+}
+
+type BBResult struct {
+	Exists uint64
+	Red    uint64
+	King   uint64
+}
+
+// MaxJumps bounds the length of a capture sequence; a side only has 12 pieces to jump.
+const MaxJumps = 12
+
+// Move is a whole turn. For a capture it holds the complete jump sequence:
+// ToX, ToY is the final landing square, Captured marks every piece jumped over
+// and Landing gives the squares visited along the way.
+type Move struct {
+	FromX, FromY int
+	ToX, ToY     int
+	IsJump       bool
+	MovedPiece   BBResult
+	IsSuperior   bool
+	Captured     uint64 // bit x+y*8 set for each captured piece
+	Jumps        int
+	path         [MaxJumps]uint8 // landing square of each jump as x+y*8
+}
+
+func (b *Board) Save() {
+	b.bbStack = append(b.bbStack, undoState{*b.BitBoard, len(b.history), len(b.moves), b.irreversible})
+	b.PlyCount++
+}
+
+// Load pops the last BitBoard state from the stack and restores it as the current state.
+func (b *Board) Load() {
+	// Ensure there is a state to load
+	if len(b.bbStack) == 0 {
+		return
+	}
+	// Pop the last state
+	lastIndex := len(b.bbStack) - 1
+	last := b.bbStack[lastIndex]
+	*b.BitBoard = last.bitBoard
+	b.history = b.history[:last.historyLen]
+	b.moves = b.moves[:last.movesLen]
+	b.irreversible = last.irreversible
+	b.bbStack = b.bbStack[:lastIndex] // Remove the last element
+	b.PlyCount--
+}
+
+func (m *Move) Equals(m2 Move) bool {
+	return m.FromX == m2.FromX &&
+		m.FromY == m2.FromY &&
+		m.ToX == m2.ToX &&
+		m.ToY == m2.ToY &&
+		m.Captured == m2.Captured &&
+		m.path == m2.path
+}
+
+// IsNull reports whether m is the zero Move rather than a generated one.
+func (m *Move) IsNull() bool {
+	return m.MovedPiece.Exists == 0
+}
+
+// Landing returns the square reached by the i-th jump of a capture.
+func (m *Move) Landing(i int) (x, y int) {
+	return int(m.path[i] % 8), int(m.path[i] / 8)
+}
+
+// Promotes reports whether the move ends on the far row with a man.
+func (m *Move) Promotes() bool {
+	return m.MovedPiece.King == 0 && isKingRow(m.MovedPiece.Red, m.ToY)
+}
+
+func isKingRow(red uint64, y int) bool {
+	return (red == 1 && y == 0) || (red == 0 && y == BoardSize-1)
+}
+
+func (m *Move) MakeMove(b *Board) {
+	king := m.MovedPiece.King
+	if m.Promotes() {
+		king = 1
+	}
+	b.BitBoard.Clear(m.FromX, m.FromY)
+	b.BitBoard.Set(m.ToX, m.ToY, 1, m.MovedPiece.Red, king)
+	// Jumped pieces come off together once the sequence is complete
+	b.BitBoard.Hash ^= b.BitBoard.piecesHash(m.Captured)
+	b.BitBoard.Exists &^= m.Captured
+	b.BitBoard.Red &^= m.Captured
+	b.BitBoard.King &^= m.Captured
+	b.BitBoard.SetRedTurn(!b.BitBoard.IsRedTurn)
+
+	// Captures and man moves can never be undone, so no earlier position can repeat
+	if m.IsJump || m.MovedPiece.King == 0 {
+		b.irreversible = len(b.history)
+	}
+	b.history = append(b.history, *b.BitBoard)
+	b.moves = append(b.moves, *m)
+}
+
+// Clone returns a copy of b that shares nothing with it, so a search can run
+// on the copy while b is drawn or played on. The undo stack is not copied.
+func (b *Board) Clone() *Board {
+	bb := *b.BitBoard
+	return &Board{
+		BitBoard:     &bb,
+		PlyCount:     b.PlyCount,
+		NodeBudget:   b.NodeBudget,
+		DrawRules:    b.DrawRules,
+		history:      append([]BitBoard(nil), b.history...),
+		moves:        append([]Move(nil), b.moves...),
+		irreversible: b.irreversible,
+		agreedDraw:   b.agreedDraw,
+	}
+}
+
+// Moves returns every move played on the board so far.
+func (b *Board) Moves() []Move {
+	return append([]Move(nil), b.moves...)
+}
+
+// LastMove returns the move that led to the current position, if any, without
+// copying the move list.
+func (b *Board) LastMove() (Move, bool) {
+	if len(b.moves) == 0 {
+		return Move{}, false
+	}
+	return b.moves[len(b.moves)-1], true
+}
+
+// StartPosition returns the position the game on this board started from.
+func (b *Board) StartPosition() BitBoard {
+	if len(b.history) == 0 {
+		return *b.BitBoard
+	}
+	return b.history[0]
+}
+
+func (bb *BitBoard) Set(x int, y int, exists uint64, red uint64, king uint64) {
+	bb.Clear(x, y)
+	shifter := uint64(1) << (x + y*8)
+	bb.Exists |= exists * shifter
+	bb.Red |= red * shifter
+	bb.King |= king * shifter
+	bb.Hash ^= bb.piecesHash(bb.Exists & shifter)
+}
+
+func (bb *BitBoard) Clear(x int, y int) {
+	shifter := uint64(1) << (x + y*8)
+	bb.Hash ^= bb.piecesHash(bb.Exists & shifter)
+	bb.Exists &^= shifter
+	bb.Red &^= shifter
+	bb.King &^= shifter
+}
+
+func (bb *BitBoard) Get(x int, y int) BBResult {
+	shifter := uint64(1) << (x + y*8)
+	out := BBResult{}
+	if (bb.Exists & shifter) != 0 {
+		out.Exists = 1
+	}
+	if (bb.Red & shifter) != 0 {
+		out.Red = 1
+	}
+	if (bb.King & shifter) != 0 {
+		out.King = 1
+	}
+	return out
+}
+
+// NewBoard initializes a new board with pieces in starting positions
+func NewBoard() *Board {
+	var start BitBoard
+	start.SetRedTurn(true)
+	for i := 0; i < BoardSize; i++ {
+		for j := 0; j < BoardSize; j++ {
+			// Add pieces to the board in starting positions
+			if (i+j)%2 != 0 {
+				if j < 3 {
+					start.Set(i, j, 1, 0, 0)
+				} else if j > 4 {
+					start.Set(i, j, 1, 1, 0)
+				}
+			}
+		}
+	}
+	return NewBoardFromPosition(start)
+}
+
+// NewBoardFromPosition starts a game from an arbitrary position.
+func NewBoardFromPosition(start BitBoard) *Board {
+	start.Hash = start.ComputeHash()
+	pos := start
+	return &Board{
+		BitBoard:  &pos,
+		DrawRules: DefaultDrawRules,
+		history:   []BitBoard{start},
+	}
+}
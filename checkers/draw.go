@@ -0,0 +1,52 @@
+package checkers
+
+// DrawRules configures when a game is drawn without either side being stuck.
+// A zero value for either rule turns it off.
+type DrawRules struct {
+	Repetitions     int // the same position with the same side to move this many times
+	NoProgressMoves int // moves by each side with only kings moving and nothing captured
+}
+
+var DefaultDrawRules = DrawRules{Repetitions: 3, NoProgressMoves: 40}
+
+// AgreeDraw ends the game as a draw by agreement of both players.
+func (b *Board) AgreeDraw() {
+	b.agreedDraw = true
+}
+
+// Repetitions counts how many times the current position has occurred,
+// including now. Only positions since the last capture or man move can match.
+func (b *Board) Repetitions() int {
+	count := 1
+	if len(b.history) == 0 {
+		return count
+	}
+	for i := len(b.history) - 2; i >= b.irreversible; i-- {
+		if b.history[i] == *b.BitBoard {
+			count++
+		}
+	}
+	return count
+}
+
+// NoProgressPlies is the number of plies played since the last capture or man move.
+func (b *Board) NoProgressPlies() int {
+	if len(b.history) == 0 {
+		return 0
+	}
+	return len(b.history) - 1 - b.irreversible
+}
+
+// IsDraw reports whether any of the draw rules has ended the game, and why.
+func (b *Board) IsDraw() (bool, string) {
+	if b.agreedDraw {
+		return true, "agreement"
+	}
+	if b.DrawRules.Repetitions > 0 && b.Repetitions() >= b.DrawRules.Repetitions {
+		return true, "repetition"
+	}
+	if b.DrawRules.NoProgressMoves > 0 && b.NoProgressPlies() >= 2*b.DrawRules.NoProgressMoves {
+		return true, "no progress"
+	}
+	return false, ""
+}
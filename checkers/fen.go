@@ -0,0 +1,111 @@
+package checkers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FEN formats the position as a PDN FEN string such as "W:W21,22,K30:B1,2,K5".
+// As with PDN, red is written as B and the other side as W.
+func (bb *BitBoard) FEN() string {
+	var white, black []string
+	for n := 1; n <= 32; n++ {
+		x, y, _ := SquareCoords(n)
+		piece := bb.Get(x, y)
+		if piece.Exists == 0 {
+			continue
+		}
+		sq := strconv.Itoa(n)
+		if piece.King == 1 {
+			sq = "K" + sq
+		}
+		if piece.Red == 1 {
+			black = append(black, sq)
+		} else {
+			white = append(white, sq)
+		}
+	}
+	turn := "W"
+	if bb.IsRedTurn {
+		turn = "B"
+	}
+	return turn + ":W" + strings.Join(white, ",") + ":B" + strings.Join(black, ",")
+}
+
+// ParseFEN reads a PDN FEN string. Square lists may use ranges ("1-12") and the
+// two colour sections may come in either order.
+func ParseFEN(fen string) (BitBoard, error) {
+	var bb BitBoard
+	fen = strings.TrimSuffix(strings.Trim(strings.TrimSpace(fen), `"`), ".")
+	fields := strings.Split(fen, ":")
+	if len(fields) > 3 {
+		return bb, fmt.Errorf("checkers: bad FEN %q", fen)
+	}
+	switch strings.TrimSpace(fields[0]) {
+	case "B":
+		bb.SetRedTurn(true)
+	case "W":
+		bb.SetRedTurn(false)
+	default:
+		return bb, fmt.Errorf("checkers: bad side to move in FEN %q", fen)
+	}
+
+	for _, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return bb, fmt.Errorf("checkers: empty colour section in FEN %q", fen)
+		}
+		var red uint64
+		switch field[0] {
+		case 'B':
+			red = 1
+		case 'W':
+			red = 0
+		default:
+			return bb, fmt.Errorf("checkers: bad colour %q in FEN %q", field[0], fen)
+		}
+		for _, item := range strings.Split(field[1:], ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			var king uint64
+			if item[0] == 'K' {
+				king = 1
+				item = item[1:]
+			}
+			first, last, isRange := strings.Cut(item, "-")
+			from, err := strconv.Atoi(first)
+			if err != nil {
+				return bb, fmt.Errorf("checkers: bad square %q in FEN %q", item, fen)
+			}
+			to := from
+			if isRange {
+				if to, err = strconv.Atoi(last); err != nil {
+					return bb, fmt.Errorf("checkers: bad square %q in FEN %q", item, fen)
+				}
+			}
+			for n := from; n <= to; n++ {
+				x, y, ok := SquareCoords(n)
+				if !ok {
+					return bb, fmt.Errorf("checkers: square %d out of range in FEN %q", n, fen)
+				}
+				if bb.Get(x, y).Exists == 1 {
+					return bb, fmt.Errorf("checkers: square %d listed twice in FEN %q", n, fen)
+				}
+				bb.Set(x, y, 1, red, king)
+			}
+		}
+	}
+	return bb, nil
+}
+
+// NewBoardFromFEN starts a game from a PDN FEN position.
+func NewBoardFromFEN(fen string) (*Board, error) {
+	bb, err := ParseFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoardFromPosition(bb), nil
+}
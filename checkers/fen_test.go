@@ -0,0 +1,91 @@
+package checkers
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFENRoundTrip(t *testing.T) {
+	for _, fen := range []string{
+		"B:W21,22,23,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,10,11,12",
+		"W:W21,22,K30:B1,2,K5",
+		"B:WK1,K32:BK4,K29",
+		"W:W:B9",
+		"B:W18:B",
+	} {
+		bb, err := ParseFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bb.FEN(); got != fen {
+			t.Errorf("%s came back as %s", fen, got)
+		}
+	}
+
+	if start := NewBoard().StartPosition(); start.FEN() != "B:W21,22,23,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,10,11,12" {
+		t.Errorf("the start position is %s", start.FEN())
+	}
+}
+
+func TestFENPlayouts(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for game := 0; game < 100; game++ {
+		b := NewBoard()
+		for ply := 0; ply < 150; ply++ {
+			moves := b.GenerateAllMoves()
+			if len(moves) == 0 {
+				break
+			}
+			moves[r.Intn(len(moves))].MakeMove(b)
+			got, err := ParseFEN(b.BitBoard.FEN())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != *b.BitBoard {
+				t.Fatalf("game %d ply %d: %s read back as\n%+v\nwant\n%+v", game, ply, b.BitBoard.FEN(), got, *b.BitBoard)
+			}
+		}
+	}
+}
+
+func TestFENRanges(t *testing.T) {
+	want, err := ParseFEN("W:W21,22,23,24,K30:B1,2,3,4,K9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fen := range []string{
+		"W:W21-24,K30:B1-4,K9",
+		"W:B1-4,K9:W21-24,K30",
+		` "W:W21-24, K30:B1-4,K9." `,
+	} {
+		got, err := ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("%s: %v", fen, err)
+		}
+		if got != want {
+			t.Errorf("%s read as %s, want %s", fen, got.FEN(), want.FEN())
+		}
+	}
+}
+
+func TestFENErrors(t *testing.T) {
+	for _, tc := range []struct {
+		fen, err string
+	}{
+		{"B:W21,21:B1", "twice"},
+		{"B:W21:B1-4,3", "twice"},
+		{"B:W0:B1", "out of range"},
+		{"B:W21:B33", "out of range"},
+		{"B:W30-33:B1", "out of range"},
+		{"R:W21:B1", "side to move"},
+		{":W21:B1", "side to move"},
+		{"B:X21:B1", "colour"},
+		{"B:W21:B1:W22", "bad FEN"},
+		{"B:Wx:B1", "bad square"},
+	} {
+		if _, err := ParseFEN(tc.fen); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: got %v, want an error mentioning %q", tc.fen, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package checkers
+
+import "math/bits"
+
+// Playable is the mask of the 32 dark squares, bit x+y*8 for each.
+const Playable uint64 = 0x55AA55AA55AA55AA
+
+// The four diagonal directions, in the same order as the (dx, dy) pairs
+// {1, 1}, {-1, 1}, {1, -1}, {-1, -1}. A step in direction d moves a square
+// index by shifts[d].
+var shifts = [4]int{9, 7, -7, -9}
+
+var (
+	stepMask [4]uint64 // playable squares with a neighbour in direction d
+	jumpMask [4]uint64 // playable squares with a square two steps away in direction d
+)
+
+func init() {
+	dxs := [4]int{1, -1, 1, -1}
+	dys := [4]int{1, 1, -1, -1}
+	for sq := 0; sq < 64; sq++ {
+		if Playable&(uint64(1)<<sq) == 0 {
+			continue
+		}
+		x, y := sq%8, sq/8
+		for d := 0; d < 4; d++ {
+			if onBoard(x+dxs[d], y+dys[d]) {
+				stepMask[d] |= uint64(1) << sq
+			}
+			if onBoard(x+2*dxs[d], y+2*dys[d]) {
+				jumpMask[d] |= uint64(1) << sq
+			}
+		}
+	}
+}
+
+func onBoard(x, y int) bool {
+	return x >= 0 && x < BoardSize && y >= 0 && y < BoardSize
+}
+
+func shift(m uint64, s int) uint64 {
+	if s > 0 {
+		return m << s
+	}
+	return m >> -s
+}
+
+// movers returns the pieces out of own that may move in direction d. Men only
+// go forward: up the board (d = 2, 3) for red and down (d = 0, 1) for black.
+func (bb *BitBoard) movers(own uint64, red bool, d int) uint64 {
+	if (d >= 2) == red {
+		return own
+	}
+	return own & bb.King
+}
+
+// canGo reports whether a single piece may move in direction d.
+func canGo(piece BBResult, d int) bool {
+	return piece.King == 1 || (d >= 2) == (piece.Red == 1)
+}
+
+// sides returns the pieces of the side to move and of its opponent.
+func (bb *BitBoard) sides() (own, opp uint64) {
+	if bb.IsRedTurn {
+		return bb.Exists & bb.Red, bb.Exists &^ bb.Red
+	}
+	return bb.Exists &^ bb.Red, bb.Exists & bb.Red
+}
+
+// jumpers returns the pieces out of own that have a capture available.
+func (bb *BitBoard) jumpers(own, opp uint64, red bool) uint64 {
+	empty := Playable &^ bb.Exists
+	var out uint64
+	for d := 0; d < 4; d++ {
+		s := shifts[d]
+		out |= bb.movers(own, red, d) & jumpMask[d] & shift(shift(empty, -s)&opp, -s)
+	}
+	return out
+}
+
+// AppendMoves appends every legal move for the side to move to dst and returns
+// the extended slice. Captures are compulsory, so if any exist only they are
+// returned. Given a dst with enough capacity it does not allocate.
+func (b *Board) AppendMoves(dst []Move) []Move {
+	bb := b.BitBoard
+	red := bb.IsRedTurn
+	own, opp := bb.sides()
+
+	if jumpers := bb.jumpers(own, opp, red); jumpers != 0 {
+		for jumpers != 0 {
+			sq := bits.TrailingZeros64(jumpers)
+			jumpers &= jumpers - 1
+			dst = b.appendJumps(dst, sq)
+		}
+		return dst
+	}
+
+	empty := Playable &^ bb.Exists
+	for movers := own; movers != 0; movers &= movers - 1 {
+		sq := bits.TrailingZeros64(movers)
+		dst = b.appendSteps(dst, sq, empty)
+	}
+	return dst
+}
+
+// appendSteps appends the non-capturing moves of the piece on sq.
+func (b *Board) appendSteps(dst []Move, sq int, empty uint64) []Move {
+	bb := b.BitBoard
+	from := uint64(1) << sq
+	piece := bb.Get(sq%8, sq/8)
+	for d := 0; d < 4; d++ {
+		if !canGo(piece, d) || from&stepMask[d] == 0 {
+			continue
+		}
+		to := sq + shifts[d]
+		if empty&(uint64(1)<<to) != 0 {
+			dst = append(dst, Move{FromX: sq % 8, FromY: sq / 8, ToX: to % 8, ToY: to / 8, MovedPiece: piece})
+		}
+	}
+	return dst
+}
+
+// appendJumps appends every complete capture sequence of the piece on sq.
+func (b *Board) appendJumps(dst []Move, sq int) []Move {
+	bb := b.BitBoard
+	piece := bb.Get(sq%8, sq/8)
+	var opp uint64
+	if piece.Red == 1 {
+		opp = bb.Exists &^ bb.Red
+	} else {
+		opp = bb.Exists & bb.Red
+	}
+	// The starting square is empty while the piece is jumping
+	occupied := bb.Exists &^ (uint64(1) << sq)
+	m := Move{FromX: sq % 8, FromY: sq / 8, ToX: sq % 8, ToY: sq / 8, IsJump: true, MovedPiece: piece}
+	return extendJumps(dst, m, sq, occupied, opp)
+}
+
+// extendJumps continues the capture sequence m from sq and appends every sequence
+// that cannot be taken any further. A man that reaches the far row stops there.
+func extendJumps(dst []Move, m Move, sq int, occupied, opp uint64) []Move {
+	from := uint64(1) << sq
+	extended := false
+	if m.Jumps < MaxJumps {
+		for d := 0; d < 4; d++ {
+			if !canGo(m.MovedPiece, d) || from&jumpMask[d] == 0 {
+				continue
+			}
+			s := shifts[d]
+			capBit := shift(from, s)
+			toBit := shift(capBit, s)
+			if opp&^m.Captured&capBit == 0 || occupied&toBit != 0 {
+				continue
+			}
+			to := sq + 2*s
+			next := m
+			next.ToX, next.ToY = to%8, to/8
+			next.Captured |= capBit
+			next.path[next.Jumps] = uint8(to)
+			next.Jumps++
+			extended = true
+			if next.Promotes() {
+				dst = append(dst, next)
+			} else {
+				dst = extendJumps(dst, next, to, occupied, opp)
+			}
+		}
+	}
+	if !extended && m.Jumps > 0 {
+		dst = append(dst, m)
+	}
+	return dst
+}
+
+// GenerateAllMoves returns every legal move for the side to move.
+func (b *Board) GenerateAllMoves() []Move {
+	return b.AppendMoves(nil)
+}
+
+// HasJump reports whether the piece at x, y has a capture available.
+func (b *Board) HasJump(x, y int) bool {
+	bb := b.BitBoard
+	from := uint64(1) << (x + y*8)
+	if bb.Exists&from == 0 {
+		return false
+	}
+	red := bb.Red&from != 0
+	opp := bb.Exists &^ bb.Red
+	if !red {
+		opp = bb.Exists & bb.Red
+	}
+	return bb.jumpers(from, opp, red) != 0
+}
+
+// MoveGenerationAt returns the simple moves and complete capture sequences of the piece
+// at x, y, whether or not the piece belongs to the side to move.
+func (b *Board) MoveGenerationAt(x, y int) []Move {
+	sq := x + y*8
+	if b.BitBoard.Exists&(uint64(1)<<sq) == 0 {
+		return nil
+	}
+	moves := b.appendJumps(nil, sq)
+	return b.appendSteps(moves, sq, Playable&^b.BitBoard.Exists)
+}
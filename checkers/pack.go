@@ -0,0 +1,71 @@
+package checkers
+
+// A packed move fits in a uint64 so it can be stored atomically, for example
+// in a transposition table shared between threads. Bits 0-5 hold the from
+// square as x+y*8, bits 6-9 the number of jumps and from bit 10 on each step
+// takes two bits giving its direction. Bit 40 marks a move as present, so the
+// null move packs to zero.
+const (
+	packJumpsShift = 6
+	packDirShift   = 10
+	packPresent    = uint64(1) << 40
+)
+
+// Pack returns m in packed form. The null move packs to zero.
+func (m *Move) Pack() uint64 {
+	if m.IsNull() {
+		return 0
+	}
+	from := m.FromX + m.FromY*8
+	p := uint64(from) | uint64(m.Jumps)<<packJumpsShift | packPresent
+	if m.Jumps == 0 {
+		return p | uint64(direction(from, m.ToX+m.ToY*8))<<packDirShift
+	}
+	sq := from
+	for i := 0; i < m.Jumps; i++ {
+		to := int(m.path[i])
+		p |= uint64(direction(sq, to)) << (packDirShift + 2*i)
+		sq = to
+	}
+	return p
+}
+
+// direction returns the d for which to lies in direction d from from.
+func direction(from, to int) int {
+	dx, dy := to%8-from%8, to/8-from/8
+	switch {
+	case dx > 0 && dy > 0:
+		return 0
+	case dx < 0 && dy > 0:
+		return 1
+	case dx > 0:
+		return 2
+	}
+	return 3
+}
+
+// UnpackMove rebuilds a packed move for the position it was made in.
+func (bb *BitBoard) UnpackMove(p uint64) Move {
+	if p&packPresent == 0 {
+		return Move{}
+	}
+	from := int(p & 63)
+	jumps := int(p>>packJumpsShift) & 15
+	m := Move{FromX: from % 8, FromY: from / 8, MovedPiece: bb.Get(from%8, from/8)}
+	if jumps == 0 {
+		to := from + shifts[p>>packDirShift&3]
+		m.ToX, m.ToY = to%8, to/8
+		return m
+	}
+	m.IsJump = true
+	sq := from
+	for i := 0; i < jumps && i < MaxJumps; i++ {
+		s := shifts[p>>(packDirShift+2*i)&3]
+		m.Captured |= uint64(1) << (sq + s)
+		sq += 2 * s
+		m.path[i] = uint8(sq)
+	}
+	m.Jumps = jumps
+	m.ToX, m.ToY = sq%8, sq/8
+	return m
+}
@@ -0,0 +1,44 @@
+package checkers
+
+// Perft counts the positions reached after exactly depth moves. Comparing the
+// counts with published figures checks the move generator and MakeMove.
+func (b *Board) Perft(depth int) uint64 {
+	if depth <= 0 {
+		return 1
+	}
+	return b.perft(depth, make([][]Move, depth+1))
+}
+
+func (b *Board) perft(depth int, bufs [][]Move) uint64 {
+	bufs[depth] = b.AppendMoves(bufs[depth][:0])
+	if depth == 1 {
+		return uint64(len(bufs[depth]))
+	}
+	var nodes uint64
+	for i := range bufs[depth] {
+		b.Save()
+		bufs[depth][i].MakeMove(b)
+		nodes += b.perft(depth-1, bufs)
+		b.Load()
+	}
+	return nodes
+}
+
+// PerftDivide is the perft count below one root move.
+type PerftDivide struct {
+	Move  Move
+	Nodes uint64
+}
+
+// Divide runs perft to depth separately under each legal move, which narrows
+// a wrong total down to the move that causes it.
+func (b *Board) Divide(depth int) []PerftDivide {
+	var out []PerftDivide
+	for _, move := range b.GenerateAllMoves() {
+		b.Save()
+		move.MakeMove(b)
+		out = append(out, PerftDivide{move, b.Perft(depth - 1)})
+		b.Load()
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package checkers
+
+import "testing"
+
+// Published perft figures for English checkers from the starting position,
+// counting a multi-jump as a single move.
+var startPerft = []uint64{1, 7, 49, 302, 1469, 7361, 36768, 179740, 845931, 3963680, 18391564}
+
+func TestPerftStartPosition(t *testing.T) {
+	for depth, want := range startPerft {
+		if depth > 8 && testing.Short() {
+			break
+		}
+		if got := NewBoard().Perft(depth); got != want {
+			t.Errorf("perft(%d) = %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestDivideSumsToPerft(t *testing.T) {
+	b := NewBoard()
+	var total uint64
+	for _, d := range b.Divide(6) {
+		total += d.Nodes
+	}
+	if total != startPerft[6] {
+		t.Errorf("divide(6) sums to %d, want %d", total, startPerft[6])
+	}
+}
+
+func TestPerftLeavesBoardUnchanged(t *testing.T) {
+	b := NewBoard()
+	before := *b.BitBoard
+	b.Perft(5)
+	if *b.BitBoard != before || len(b.Moves()) != 0 || b.PlyCount != 0 {
+		t.Errorf("perft changed the board")
+	}
+}
+
+func TestForcedCapture(t *testing.T) {
+	// Red's man on 11 must take the man on 15 even though other moves exist
+	b, err := NewBoardFromFEN("B:W15:B11,1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := b.GenerateAllMoves()
+	if len(moves) != 1 || moves[0].String() != "11x18" {
+		t.Fatalf("moves = %v, want [11x18]", moves)
+	}
+}
+
+func TestMultiJumpIsOneMove(t *testing.T) {
+	b, err := NewBoardFromFEN("B:W15,23:B11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := b.GenerateAllMoves()
+	if len(moves) != 1 || moves[0].String() != "11x18x27" || moves[0].Jumps != 2 {
+		t.Fatalf("moves = %v, want [11x18x27]", moves)
+	}
+	moves[0].MakeMove(b)
+	if got := b.BitBoard.FEN(); got != "W:W:B27" {
+		t.Errorf("after the jump FEN = %q, want %q", got, "W:W:B27")
+	}
+	if b.Perft(1) != 0 {
+		t.Errorf("white should have no moves left")
+	}
+}
+
+func TestPromotionEndsJump(t *testing.T) {
+	// Jumping 26 crowns the man on 31; as a king it could go on to take 27,
+	// but a move that crowns a man ends there.
+	b, err := NewBoardFromFEN("B:W26,27:B22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := b.GenerateAllMoves()
+	if len(moves) != 1 || moves[0].String() != "22x31" {
+		t.Fatalf("moves = %v, want [22x31]", moves)
+	}
+	moves[0].MakeMove(b)
+	if got := b.BitBoard.FEN(); got != "W:W27:BK31" {
+		t.Errorf("after crowning FEN = %q, want %q", got, "W:W27:BK31")
+	}
+}
+
+func TestKingMovesBackwards(t *testing.T) {
+	b, err := NewBoardFromFEN("B:W1:BK14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Perft(1); got != 4 {
+		t.Errorf("perft(1) = %d, want 4", got)
+	}
+}
@@ -0,0 +1,64 @@
+package checkers
+
+// Status is the state of a game: still being played, won by one side, or drawn.
+type Status int
+
+const (
+	Ongoing Status = iota
+	RedWins
+	BlackWins
+	Draw
+)
+
+func (s Status) String() string {
+	switch s {
+	case RedWins:
+		return "Red wins"
+	case BlackWins:
+		return "Black wins"
+	case Draw:
+		return "Draw"
+	}
+	return "Ongoing"
+}
+
+// Result is a game status together with the reason it was reached.
+type Result struct {
+	Status Status
+	Reason string
+}
+
+func (r Result) String() string {
+	if r.Reason == "" {
+		return r.Status.String()
+	}
+	return r.Status.String() + " (" + r.Reason + ")"
+}
+
+// IsOver reports whether the game has finished.
+func (r Result) IsOver() bool {
+	return r.Status != Ongoing
+}
+
+// Result works out whether the side to move has lost or the game is drawn. A side
+// loses when it has no pieces left or when none of its pieces has a legal move.
+func (b *Board) Result() Result {
+	red := b.BitBoard.Exists & b.BitBoard.Red
+	black := b.BitBoard.Exists &^ b.BitBoard.Red
+	if red == 0 {
+		return Result{BlackWins, "no pieces"}
+	}
+	if black == 0 {
+		return Result{RedWins, "no pieces"}
+	}
+	if len(b.GenerateAllMoves()) == 0 {
+		if b.BitBoard.IsRedTurn {
+			return Result{BlackWins, "no legal moves"}
+		}
+		return Result{RedWins, "no legal moves"}
+	}
+	if draw, reason := b.IsDraw(); draw {
+		return Result{Draw, reason}
+	}
+	return Result{Ongoing, ""}
+}
@@ -0,0 +1,137 @@
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The 32 playable squares are numbered the standard way, 1-12 being the home
+// squares of the side that moves first. Here that side is red, which plays up
+// the board from the bottom, so square 1 sits at the bottom right (x=6, y=7)
+// and square 32 at the top left (x=1, y=0). PDN calls red "Black" and the other
+// side "White".
+
+// SquareNumber returns the 1-32 square number of a playable square, or 0 for a light square.
+func SquareNumber(x, y int) int {
+	if x < 0 || x >= BoardSize || y < 0 || y >= BoardSize || (x+y)%2 == 0 {
+		return 0
+	}
+	return (7-y)*4 + (7-x)/2 + 1
+}
+
+// SquareCoords returns the board coordinates of a 1-32 square number.
+func SquareCoords(n int) (x, y int, ok bool) {
+	if n < 1 || n > 32 {
+		return 0, 0, false
+	}
+	row := (n - 1) / 4
+	y = 7 - row
+	x = 7 - ((n-1)%4)*2
+	if (x+y)%2 == 0 {
+		x--
+	}
+	return x, y, true
+}
+
+// Squares lists the square numbers a move passes through, starting square first.
+func (m *Move) Squares() []int {
+	path := []int{SquareNumber(m.FromX, m.FromY)}
+	for i := 0; i < m.Jumps; i++ {
+		x, y := m.Landing(i)
+		path = append(path, SquareNumber(x, y))
+	}
+	if m.Jumps == 0 {
+		path = append(path, SquareNumber(m.ToX, m.ToY))
+	}
+	return path
+}
+
+var (
+	ErrIllegalMove   = errors.New("illegal move")
+	ErrAmbiguousMove = errors.New("ambiguous move")
+)
+
+// FindMove returns the legal move on b through the given square numbers:
+// every square the piece stops on, or just the first and last if only one
+// move joins them. It fails with ErrIllegalMove if no move fits and with
+// ErrAmbiguousMove if more than one does.
+func (b *Board) FindMove(squares []int) (Move, error) {
+	var found *Move
+	if len(squares) < 2 {
+		return Move{}, ErrIllegalMove
+	}
+	for _, move := range b.GenerateAllMoves() {
+		path := move.Squares()
+		match := false
+		if len(squares) == 2 {
+			match = path[0] == squares[0] && path[len(path)-1] == squares[1]
+		} else if len(path) == len(squares) {
+			match = true
+			for i := range path {
+				if path[i] != squares[i] {
+					match = false
+					break
+				}
+			}
+		}
+		if match {
+			if found != nil {
+				return Move{}, ErrAmbiguousMove
+			}
+			found = &move
+		}
+	}
+	if found == nil {
+		return Move{}, ErrIllegalMove
+	}
+	return *found, nil
+}
+
+// String writes the move in square notation, such as "11-15" or "9x18x27".
+func (m Move) String() string {
+	sep := "-"
+	if m.IsJump {
+		sep = "x"
+	}
+	out := ""
+	for i, sq := range m.Squares() {
+		if i > 0 {
+			out += sep
+		}
+		out += strconv.Itoa(sq)
+	}
+	return out
+}
+
+// String draws the board as text, top row first: r and b are red and black men,
+// R and B their kings. The margin gives the square numbers along each row.
+func (bb *BitBoard) String() string {
+	out := ""
+	for y := 0; y < BoardSize; y++ {
+		row := ""
+		for x := 0; x < BoardSize; x++ {
+			piece := bb.Get(x, y)
+			c := "."
+			if (x+y)%2 == 0 {
+				c = " "
+			} else if piece.Exists == 1 {
+				c = "b"
+				if piece.Red == 1 {
+					c = "r"
+				}
+				if piece.King == 1 {
+					c = strings.ToUpper(c)
+				}
+			}
+			row += c + " "
+		}
+		lowest := (7-y)*4 + 1
+		out += fmt.Sprintf("%s  %2d..%2d\n", row, lowest+3, lowest)
+	}
+	if bb.IsRedTurn {
+		return out + "red to move\n"
+	}
+	return out + "black to move\n"
+}
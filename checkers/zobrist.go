@@ -0,0 +1,63 @@
+package checkers
+
+import "math/bits"
+
+// Zobrist keys: one per piece kind and square, plus one for red to move.
+// Piece kinds are indexed red*2+king.
+var (
+	zobristPiece [4][64]uint64
+	zobristRed   uint64
+)
+
+func init() {
+	// splitmix64 with a fixed seed, so hashes are the same from run to run
+	seed := uint64(0x9E3779B97F4A7C15)
+	next := func() uint64 {
+		seed += 0x9E3779B97F4A7C15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+	for kind := range zobristPiece {
+		for sq := range zobristPiece[kind] {
+			zobristPiece[kind][sq] = next()
+		}
+	}
+	zobristRed = next()
+}
+
+// piecesHash XORs together the keys of the pieces standing on the squares in mask.
+func (bb *BitBoard) piecesHash(mask uint64) uint64 {
+	var h uint64
+	for m := mask & bb.Exists; m != 0; m &= m - 1 {
+		sq := bits.TrailingZeros64(m)
+		kind := 0
+		if bb.Red&(uint64(1)<<sq) != 0 {
+			kind += 2
+		}
+		if bb.King&(uint64(1)<<sq) != 0 {
+			kind++
+		}
+		h ^= zobristPiece[kind][sq]
+	}
+	return h
+}
+
+// SetRedTurn sets the side to move, keeping Hash in step.
+func (bb *BitBoard) SetRedTurn(red bool) {
+	if red != bb.IsRedTurn {
+		bb.Hash ^= zobristRed
+	}
+	bb.IsRedTurn = red
+}
+
+// ComputeHash works out the Zobrist key from scratch. Hash should always equal it;
+// use it after changing the masks or IsRedTurn directly.
+func (bb *BitBoard) ComputeHash() uint64 {
+	h := bb.piecesHash(bb.Exists)
+	if bb.IsRedTurn {
+		h ^= zobristRed
+	}
+	return h
+}
@@ -0,0 +1,63 @@
+package checkers
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestZobristIncrementalMatchesFull(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for game := 0; game < 200; game++ {
+		b := NewBoard()
+		for ply := 0; ply < 150; ply++ {
+			moves := b.GenerateAllMoves()
+			if len(moves) == 0 {
+				break
+			}
+			b.Save()
+			moves[r.Intn(len(moves))].MakeMove(b)
+			if b.BitBoard.Hash != b.BitBoard.ComputeHash() {
+				t.Fatalf("game %d ply %d: incremental hash %x, full hash %x (%s)", game, ply, b.BitBoard.Hash, b.BitBoard.ComputeHash(), b.BitBoard.FEN())
+			}
+		}
+		for len(b.Moves()) > 0 {
+			b.Load()
+		}
+		if *b.BitBoard != NewBoard().StartPosition() {
+			t.Fatalf("game %d: hash not restored by Load", game)
+		}
+	}
+}
+
+func TestZobristNoCollisions(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	seen := map[uint64]BitBoard{}
+	games := 2000
+	if testing.Short() {
+		games = 200
+	}
+	for game := 0; game < games; game++ {
+		b := NewBoard()
+		for ply := 0; ply < 200; ply++ {
+			moves := b.GenerateAllMoves()
+			if len(moves) == 0 {
+				break
+			}
+			moves[r.Intn(len(moves))].MakeMove(b)
+			pos := *b.BitBoard
+			if other, ok := seen[pos.Hash]; ok && other != pos {
+				t.Fatalf("hash %x shared by %s and %s", pos.Hash, other.FEN(), pos.FEN())
+			}
+			seen[pos.Hash] = pos
+		}
+	}
+	t.Logf("%d distinct positions", len(seen))
+}
+
+func TestZobristSideToMove(t *testing.T) {
+	red, _ := ParseFEN("B:W21:B1")
+	black, _ := ParseFEN("W:W21:B1")
+	if red.Hash == black.Hash {
+		t.Errorf("side to move does not change the hash")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/players"
+)
+
+func runAnalyse(args []string) error {
+	fs := newFlagSet("analyse")
+	fen := fs.String("fen", "", "analyse this PDN FEN position instead of the opening")
+	name := fs.String("engine", "negascout", "engine to analyse with")
+	multiPV := fs.Int("multipv", 3, "number of best moves to rank")
+	moveTime := fs.Duration("movetime", 5*time.Second, "time to think")
+	depth := fs.Int("depth", 0, "deepest iteration to search, 0 for no limit")
+	threads := fs.Int("threads", 1, "search threads, if the engine supports them")
+	egdb := fs.String("egdb", "", "consult this endgame database")
+	dtw := fs.String("dtw", "", "consult these endgame distance-to-win tables")
+	verbose := fs.Bool("v", false, "also show the engine's own log")
+	fs.Parse(args)
+
+	b := checkers.NewBoard()
+	if *fen != "" {
+		var err error
+		if b, err = checkers.NewBoardFromFEN(*fen); err != nil {
+			return err
+		}
+	}
+	e, err := players.New(*name)
+	if err != nil {
+		return err
+	}
+	if _, ok := e.(*engine.Human); ok {
+		return fmt.Errorf("cannot analyse with a human player")
+	}
+	if t, ok := e.(engine.Threaded); ok {
+		t.SetThreads(*threads)
+	}
+	if err := useFile(e, "egdb", *egdb); err != nil {
+		return err
+	}
+	if err := useFile(e, "dtw", *dtw); err != nil {
+		return err
+	}
+
+	// Show each finished iteration as it comes
+	if r, ok := e.(engine.Reporting); ok {
+		if !*verbose {
+			r.SetLogger(engine.NopLogger)
+		}
+		defer r.Subscribe(func(info engine.SearchInfo) {
+			fmt.Printf("depth %2d/%-2d %10s %9d nodes %8d nps  %s\n",
+				info.Depth, info.SelDepth, engine.FormatScore(info.Score), info.Nodes, info.NPS, engine.FormatPV(info.PV))
+		})()
+	}
+
+	fmt.Print(b.BitBoard)
+	limits := engine.SearchLimits{MoveTime: *moveTime, Depth: *depth, MultiPV: *multiPV}
+	if _, err := e.ChooseMove(context.Background(), b, limits); err != nil {
+		return err
+	}
+	info := e.Info()
+	fmt.Printf("\ndepth %d  nodes %d  time %s\n", info.Depth, info.Nodes, info.Time.Round(time.Millisecond))
+	for i, l := range info.Lines {
+		fmt.Printf("%d. %s\n", i+1, l)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+func runBallots(args []string) error {
+	fs := newFlagSet("ballots")
+	name := fs.String("engine", "negascout", "engine to judge the openings with")
+	depth := fs.Int("depth", 16, "depth to search each opening to")
+	margin := fs.Float64("margin", 1, "leave out openings the search scores this far ahead for either side, a man being 1")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a deck file")
+	}
+	path := fs.Arg(0)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Three-move ballots for play, one to a line: the distinct three-move openings\n")
+	fmt.Fprintf(&sb, "# less those a %d-ply %s search scores %g or more ahead for either side.\n", *depth, *name, *margin)
+	fmt.Fprintf(&sb, "# Made by \"checkers ballots -engine %s -depth %d -margin %g\".\n", *name, *depth, *margin)
+	kept, first := 0, ""
+	for _, bl := range checkers.AllThreeMoveOpenings() {
+		b, err := checkers.NewBoardFromBallot(bl)
+		if err != nil {
+			return err
+		}
+		e, err := newMatchEngine(*name, "")
+		if err != nil {
+			return err
+		}
+		if _, err := e.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: *depth}); err != nil {
+			return err
+		}
+		score := e.Info().Score
+		if math.Abs(score) >= *margin {
+			fmt.Printf("%-20s %6.2f  left out\n", bl, score)
+			continue
+		}
+		fmt.Printf("%-20s %6.2f\n", bl, score)
+		if bl[0] != first {
+			sb.WriteString("\n")
+			first = bl[0]
+		}
+		sb.WriteString(bl.String() + "\n")
+		kept++
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("\nwrote %s, %d ballots\n", path, kept)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"myproject/engine"
+	"myproject/engine/players"
+)
+
+func runBench(args []string) error {
+	fs := newFlagSet("bench")
+	name := fs.String("engine", "negascout", "engine to measure")
+	depth := fs.Int("depth", engine.BenchDepth, "depth to search each position to")
+	threads := fs.Int("threads", 1, "search threads, if the engine supports them; counts only repeat with one")
+	fs.Parse(args)
+
+	e, err := players.New(*name)
+	if err != nil {
+		return err
+	}
+	if _, ok := e.(*engine.Human); ok {
+		return fmt.Errorf("cannot bench a human player")
+	}
+	if t, ok := e.(engine.Threaded); ok {
+		t.SetThreads(*threads)
+	}
+	if r, ok := e.(engine.Reporting); ok {
+		r.SetLogger(engine.NopLogger)
+	}
+
+	start := time.Now()
+	results, err := engine.Bench(context.Background(), e, *depth)
+	if err != nil {
+		return err
+	}
+	took := time.Since(start)
+	nodes := 0
+	for i, r := range results {
+		fmt.Printf("%2d %-8s %10s %10d nodes  %s\n", i+1, r.Move, engine.FormatScore(r.Info.Score), r.Info.Nodes, r.FEN)
+		nodes += r.Info.Nodes
+	}
+	fmt.Printf("\nnodes %d  time %s  nps %.0f\n", nodes, took.Round(time.Millisecond), float64(nodes)/took.Seconds())
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/book"
+	"myproject/engine/match"
+	"myproject/pdn"
+)
+
+func runBook(args []string) error {
+	fs := newFlagSet("book")
+	pdnFile := fs.String("pdn", "", "build the book from the games in this PDN file")
+	selfPlay := fs.Int("selfplay", 0, "build the book from this many games of the engine against itself")
+	name := fs.String("engine", "negascout", "engine for self-play")
+	moveTime := fs.Duration("movetime", 100*time.Millisecond, "thinking time per self-play move")
+	random := fs.Int("random", 2, "random moves opening each self-play game, so the games differ")
+	seed := fs.Int64("seed", 1, "seed for the random moves")
+	plies := fs.Int("plies", 12, "plies of each game to put in the book")
+	minGames := fs.Int("min", 2, "games a move must have been played in to go in the book")
+	fen := fs.String("fen", "", "list the book moves from this PDN FEN position instead of the opening")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a book file")
+	}
+	path := fs.Arg(0)
+
+	var bk *book.Book
+	if *pdnFile != "" || *selfPlay > 0 {
+		bd := book.NewBuilder(*plies, *minGames)
+		if *pdnFile != "" {
+			if err := addPDN(bd, *pdnFile); err != nil {
+				return err
+			}
+		}
+		if *selfPlay > 0 {
+			if err := addSelfPlay(bd, *name, *selfPlay, *random, *seed, *moveTime); err != nil {
+				return err
+			}
+		}
+		bk = bd.Book()
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		n, err := bk.WriteTo(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s, %d positions, %d bytes\n\n", path, bk.Len(), n)
+	} else {
+		var err error
+		if bk, err = book.Open(path); err != nil {
+			return err
+		}
+	}
+
+	b := checkers.NewBoard()
+	if *fen != "" {
+		var err error
+		if b, err = checkers.NewBoardFromFEN(*fen); err != nil {
+			return err
+		}
+	}
+	choices := bk.Choices(b)
+	if len(choices) == 0 {
+		fmt.Println("the position is not in the book")
+		return nil
+	}
+	total := 0
+	for _, c := range choices {
+		total += c.Weight
+	}
+	for _, c := range choices {
+		fmt.Printf("%-8s %6d  %5.1f%%\n", c.Move.String(), c.Weight, 100*float64(c.Weight)/float64(total))
+	}
+	return nil
+}
+
+// addPDN counts the games in the named PDN file.
+func addPDN(bd *book.Builder, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	games, err := pdn.Read(f)
+	if err != nil {
+		return err
+	}
+	for i, g := range games {
+		if err := bd.AddGame(g); err != nil {
+			return fmt.Errorf("%s: game %d: %w", path, i+1, err)
+		}
+	}
+	fmt.Printf("read %d games from %s\n", len(games), path)
+	return nil
+}
+
+// addSelfPlay counts games of the named engine against itself, each opened
+// with random moves.
+func addSelfPlay(bd *book.Builder, name string, games, random int, seed int64, moveTime time.Duration) error {
+	red, err := newMatchEngine(name, "")
+	if err != nil {
+		return err
+	}
+	black, err := newMatchEngine(name, "")
+	if err != nil {
+		return err
+	}
+	limits := engine.SearchLimits{MoveTime: moveTime}
+	for i, opening := range match.RandomOpenings(games, random, seed) {
+		game, err := match.Game(context.Background(), red, black, opening, limits)
+		if err != nil {
+			return err
+		}
+		result := game.Result()
+		bd.AddBoard(game, result.Status)
+		moves := make([]string, 0, 4)
+		for _, m := range game.Moves()[:min(4, len(game.Moves()))] {
+			moves = append(moves, m.String())
+		}
+		fmt.Printf("game %3d  %-22s  %s\n", i+1, strings.Join(moves, " "), result)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine/endgame"
+)
+
+func runEgdb(args []string) error {
+	fs := newFlagSet("egdb")
+	generate := fs.Int("generate", 0, "work out every position with up to this many pieces and write them to the file")
+	dtw := fs.Bool("dtw", false, "the file holds distance-to-win tables rather than win/loss/draw")
+	verify := fs.Bool("verify", false, "check every value in the file against its successors")
+	fen := fs.String("fen", "", "look up this PDN FEN position in the file")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a database file")
+	}
+	path := fs.Arg(0)
+
+	start := time.Now()
+	progress := func(s endgame.Signature, c endgame.Counts) {
+		fmt.Printf("%-10s %12d positions  %s  %s\n", s, c.Wins+c.Losses+c.Draws, c, time.Since(start).Round(time.Second))
+	}
+	var db *endgame.DB
+	var dist *endgame.Distances
+	var err error
+	switch {
+	case *generate > 0 && *dtw:
+		if dist, err = endgame.GenerateDistances(*generate, progress); err != nil {
+			return err
+		}
+		err = writeEgdb(path, dist, start)
+	case *generate > 0:
+		db = endgame.Generate(*generate, progress)
+		err = writeEgdb(path, db, start)
+	case *dtw:
+		dist, err = endgame.OpenDistances(path)
+	default:
+		db, err = endgame.Open(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *verify {
+		start := time.Now()
+		check := func(s endgame.Signature, c endgame.Counts) {
+			fmt.Printf("%-10s ok  %s\n", s, c)
+		}
+		tables := 0
+		if dist != nil {
+			err, tables = endgame.VerifyDistances(dist, check), len(dist.Signatures())
+		} else {
+			err, tables = endgame.Verify(db, check), len(db.Signatures())
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nverified %d tables in %s\n", tables, time.Since(start).Round(time.Millisecond))
+	}
+
+	if *fen != "" {
+		b, err := checkers.NewBoardFromFEN(*fen)
+		if err != nil {
+			return err
+		}
+		if dist != nil {
+			m, v, d, ok := dist.BestMove(b)
+			switch {
+			case !ok && v == endgame.Unknown:
+				return fmt.Errorf("the tables hold positions with up to %d pieces", dist.MaxPieces)
+			case !ok:
+				fmt.Printf("%s for the side to move\n", v)
+			case v == endgame.Draw:
+				fmt.Printf("draw for the side to move, keeping it with %s\n", m)
+			default:
+				fmt.Printf("%s in %d for the side to move, best %s\n", v, d, m)
+			}
+			return nil
+		}
+		v, ok := db.Probe(b.BitBoard)
+		if !ok {
+			return fmt.Errorf("the database holds positions with up to %d pieces", db.MaxPieces)
+		}
+		fmt.Printf("%s for the side to move\n", v)
+	}
+	return nil
+}
+
+// writeEgdb writes freshly generated tables to path.
+func writeEgdb(path string, tables io.WriterTo, start time.Time) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n, err := tables.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nwrote %s, %d bytes, in %s\n", path, n, time.Since(start).Round(time.Millisecond))
+	return nil
+}
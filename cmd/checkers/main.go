@@ -0,0 +1,98 @@
+// Command checkers runs the rules engine and bots without the GUI.
+//
+//	checkers perft [-fen FEN] [-divide] depth
+//	checkers analyse [-fen FEN] [-engine NAME] [-multipv N] [-movetime D] [-depth N] [-threads N] [-egdb FILE] [-dtw FILE] [-v]
+//	checkers bench [-engine NAME] [-depth N] [-threads N]
+//	checkers ballots [-engine NAME] [-depth N] [-margin X] file
+//	checkers book [-pdn FILE] [-selfplay N] [-engine NAME] [-movetime D] [-random N] [-seed N] [-plies N] [-min N] [-fen FEN] file
+//	checkers egdb [-generate PIECES] [-dtw] [-verify] [-fen FEN] file
+//	checkers match [-a ENGINE] [-b ENGINE] [-aopt OPTS] [-bopt OPTS] [-games N] [-plies N | -ballots DECK] [-seed N] [-movetime D] [-depth N] [-nodes N] [-pdn FILE]
+//	checkers play [-red PLAYER] [-black PLAYER] [-fen FEN | -ballot BALLOT] [-movetime D | -tc TC] [-depth N] [-nodes N] [-threads N] [-egdb FILE] [-dtw FILE] [-book FILE] [-ponder] [-log FILE | -quiet] [-pdn FILE]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"myproject/engine"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+// commands is filled in by init because the commands use newFlagSet, which reads it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"perft", "perft [-fen FEN] [-divide] depth", runPerft},
+		{"analyse", "analyse [-fen FEN] [-engine NAME] [-multipv N] [-movetime D] [-depth N] [-threads N] [-egdb FILE] [-dtw FILE] [-v]", runAnalyse},
+		{"bench", "bench [-engine NAME] [-depth N] [-threads N]", runBench},
+		{"ballots", "ballots [-engine NAME] [-depth N] [-margin X] file", runBallots},
+		{"book", "book [-pdn FILE] [-selfplay N] [-engine NAME] [-movetime D] [-random N] [-seed N] [-plies N] [-min N] [-fen FEN] file", runBook},
+		{"egdb", "egdb [-generate PIECES] [-dtw] [-verify] [-fen FEN] file", runEgdb},
+		{"match", "match [-a ENGINE] [-b ENGINE] [-aopt OPTS] [-bopt OPTS] [-games N] [-plies N | -ballots DECK] [-seed N] [-movetime D] [-depth N] [-nodes N] [-pdn FILE]", runMatch},
+		{"play", "play [-red PLAYER] [-black PLAYER] [-fen FEN | -ballot BALLOT] [-movetime D | -tc TC] [-depth N] [-nodes N] [-threads N] [-egdb FILE] [-dtw FILE] [-book FILE] [-ponder] [-log FILE | -quiet] [-pdn FILE]", runPlay},
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: checkers <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintln(os.Stderr, "  "+c.usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, "checkers "+c.name+":", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+}
+
+// newFlagSet returns a flag set whose usage line comes from the command table.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintln(os.Stderr, "usage: checkers "+c.usage)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// useFile has e consult the file in path, of the kind option names: endgame
+// tables or an opening book. Nothing is done if path is empty or e has no
+// such option.
+func useFile(e engine.Engine, option, path string) error {
+	if path == "" {
+		return nil
+	}
+	c, ok := e.(engine.Configurable)
+	if !ok {
+		return nil
+	}
+	if err := c.SetOption(option, path); !errors.Is(err, engine.ErrUnknownOption) {
+		return err
+	}
+	return nil
+}
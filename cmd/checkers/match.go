@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/match"
+	"myproject/engine/players"
+	"myproject/pdn"
+)
+
+func runMatch(args []string) error {
+	fs := newFlagSet("match")
+	aName := fs.String("a", "negascout", "first engine: "+strings.Join(players.Names, ", "))
+	bName := fs.String("b", "negascout", "second engine")
+	aOpts := fs.String("aopt", "", `options for the first engine, e.g. "lmr=off,pvs=off"`)
+	bOpts := fs.String("bopt", "", "options for the second engine")
+	games := fs.Int("games", 100, "games to play, two from each opening; with -ballots, twice the deck unless set")
+	plies := fs.Int("plies", 4, "random moves played from the start to make each opening")
+	ballots := fs.String("ballots", "", `play through a deck of three-move ballots instead of random openings: "all" for the balanced deck in the checkers package, or a file of one`)
+	seed := fs.Int64("seed", 1, "seed for the random openings")
+	moveTime := fs.Duration("movetime", 100*time.Millisecond, "thinking time per move")
+	depth := fs.Int("depth", 0, "deepest iteration to search, 0 for no limit")
+	nodes := fs.Int("nodes", 0, "positions to visit per move, 0 for no limit")
+	out := fs.String("pdn", "", "append the games to this PDN file")
+	fs.Parse(args)
+
+	a, err := newMatchEngine(*aName, *aOpts)
+	if err != nil {
+		return err
+	}
+	b, err := newMatchEngine(*bName, *bOpts)
+	if err != nil {
+		return err
+	}
+	aLabel, bLabel := matchLabel(*aName, *aOpts), matchLabel(*bName, *bOpts)
+	fmt.Printf("A: %s\nB: %s\n\n", aLabel, bLabel)
+
+	var f *os.File
+	if *out != "" {
+		if f, err = os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+
+	var openings []*checkers.Board
+	if *ballots != "" {
+		set := false
+		fs.Visit(func(f *flag.Flag) { set = set || f.Name == "games" })
+		if !set {
+			*games = 0
+		}
+		if openings, err = ballotOpenings(*ballots, *games); err != nil {
+			return err
+		}
+	} else {
+		openings = match.RandomOpenings((*games+1)/2, *plies, *seed)
+	}
+	limits := engine.SearchLimits{MoveTime: *moveTime, Depth: *depth, Nodes: *nodes}
+	played := 0
+	var writeErr error
+	s, err := match.Run(context.Background(), a, b, openings, limits, func(game *checkers.Board, aRed bool, s match.Score) {
+		played++
+		red, black := "A", "B"
+		redLabel, blackLabel := aLabel, bLabel
+		if !aRed {
+			red, black = "B", "A"
+			redLabel, blackLabel = bLabel, aLabel
+		}
+		fmt.Printf("game %3d  %s-%s  %-30s  %s\n", played, red, black, game.Result(), s)
+		if f != nil && writeErr == nil {
+			// Red moves first, which PDN calls Black
+			record := pdn.FromBoard(game)
+			record.SetTag("Event", "Engine match")
+			record.SetTag("Black", redLabel)
+			record.SetTag("White", blackLabel)
+			writeErr = pdn.Write(f, record)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nA against B: %s\n", s)
+	return writeErr
+}
+
+// ballotOpenings returns the position after each ballot of the deck, in
+// order: the balanced deck if deck is "all" and otherwise the one read from
+// the file it names. A match of games 0 plays every ballot once with each
+// colour, and a longer one goes round the deck again; a shorter one is an
+// error, since it would leave part of the deck unplayed.
+func ballotOpenings(deck string, games int) ([]*checkers.Board, error) {
+	ballots := checkers.BalancedDeck()
+	if deck != "all" {
+		f, err := os.Open(deck)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if ballots, err = checkers.ReadBallots(f); err != nil {
+			return nil, fmt.Errorf("%s: %w", deck, err)
+		}
+	}
+	if games > 0 && games < 2*len(ballots) {
+		return nil, fmt.Errorf("the deck has %d ballots, so takes %d games to play through, not %d", len(ballots), 2*len(ballots), games)
+	}
+	for len(ballots) < (games+1)/2 {
+		ballots = append(ballots, ballots[:min(len(ballots), (games+1)/2-len(ballots))]...)
+	}
+	return match.Ballots(ballots)
+}
+
+// matchLabel names an engine with the options it was given.
+func matchLabel(name, opts string) string {
+	if opts == "" {
+		return name
+	}
+	return name + " (" + opts + ")"
+}
+
+// newMatchEngine builds the named engine and applies opts, a comma-separated
+// list of name=value settings.
+func newMatchEngine(name, opts string) (engine.Engine, error) {
+	e, err := players.New(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := e.(*engine.Human); ok {
+		return nil, fmt.Errorf("a match is between engines, not %s", name)
+	}
+	if r, ok := e.(engine.Reporting); ok {
+		r.SetLogger(engine.NopLogger)
+	}
+	if opts == "" {
+		return e, nil
+	}
+	c, ok := e.(engine.Configurable)
+	if !ok {
+		return nil, fmt.Errorf("%s has no options", name)
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad option %q, want name=value", opt)
+		}
+		if err := c.SetOption(strings.TrimSpace(k), strings.TrimSpace(v)); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
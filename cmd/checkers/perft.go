@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"myproject/checkers"
+)
+
+func runPerft(args []string) error {
+	fs := newFlagSet("perft")
+	fen := fs.String("fen", "", "start from this PDN FEN position instead of the opening")
+	divide := fs.Bool("divide", false, "print the node count under each root move")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a depth")
+	}
+	depth, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || depth < 1 {
+		return fmt.Errorf("bad depth %q", fs.Arg(0))
+	}
+
+	b := checkers.NewBoard()
+	if *fen != "" {
+		if b, err = checkers.NewBoardFromFEN(*fen); err != nil {
+			return err
+		}
+	}
+
+	if *divide {
+		var total uint64
+		start := time.Now()
+		for _, d := range b.Divide(depth) {
+			fmt.Printf("%-10s %d\n", d.Move, d.Nodes)
+			total += d.Nodes
+		}
+		fmt.Printf("\nnodes %d  time %s\n", total, time.Since(start).Round(time.Millisecond))
+		return nil
+	}
+
+	for d := 1; d <= depth; d++ {
+		start := time.Now()
+		nodes := b.Perft(d)
+		took := time.Since(start)
+		fmt.Printf("perft(%2d) = %12d  %10s  %8.0f kN/s\n", d, nodes, took.Round(time.Microsecond), float64(nodes)/took.Seconds()/1000)
+	}
+	return nil
+}
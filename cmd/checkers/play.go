@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/players"
+	"myproject/pdn"
+)
+
+func runPlay(args []string) error {
+	fs := newFlagSet("play")
+	redName := fs.String("red", "human", "player for red, who moves first: "+strings.Join(players.Names, ", "))
+	blackName := fs.String("black", "negascout", "player for black")
+	fen := fs.String("fen", "", "start from this PDN FEN position instead of the opening")
+	ballot := fs.String("ballot", "", `start from this three-move ballot, e.g. "11-15 23-19 8-11", or "random"`)
+	moveTime := fs.Duration("movetime", time.Second, "thinking time per engine move")
+	depth := fs.Int("depth", 0, "deepest iteration an engine may search, 0 for no limit")
+	nodes := fs.Int("nodes", 0, "positions an engine may visit per move, 0 for no limit")
+	threads := fs.Int("threads", 1, "search threads for engines that support them")
+	egdb := fs.String("egdb", "", "endgame database for engines that can consult one")
+	dtw := fs.String("dtw", "", "endgame distance-to-win tables for engines that can consult them")
+	bookFile := fs.String("book", "", "opening book for engines to play from")
+	ponder := fs.Bool("ponder", false, "let engines think while a human is choosing a move")
+	logFile := fs.String("log", "", "write the engines' search logs to this file instead of standard error")
+	quiet := fs.Bool("quiet", false, "do not log the engines' searches")
+	tcFlag := fs.String("tc", "", `play on clocks under this time control instead of -movetime, e.g. "5m+3s" or "40/10m"`)
+	out := fs.String("pdn", "", "append the finished game to this PDN file")
+	fs.Parse(args)
+
+	red, err := players.New(*redName)
+	if err != nil {
+		return err
+	}
+	black, err := players.New(*blackName)
+	if err != nil {
+		return err
+	}
+	var logger engine.Logger = engine.StderrLogger
+	switch {
+	case *quiet:
+		logger = engine.NopLogger
+	case *logFile != "":
+		f, err := os.OpenFile(*logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		logger = log.New(f, "", log.LstdFlags)
+	}
+
+	in := bufio.NewScanner(os.Stdin)
+	for _, p := range []engine.Engine{red, black} {
+		if r, ok := p.(engine.Reporting); ok {
+			r.SetLogger(logger)
+		}
+		if h, ok := p.(*engine.Human); ok {
+			h.Input = consoleInput(in)
+		}
+		if t, ok := p.(engine.Threaded); ok {
+			t.SetThreads(*threads)
+		}
+		for _, f := range [][2]string{{"egdb", *egdb}, {"dtw", *dtw}, {"book", *bookFile}} {
+			if err := useFile(p, f[0], f[1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	b := checkers.NewBoard()
+	switch {
+	case *fen != "" && *ballot != "":
+		return fmt.Errorf("start from a FEN position or a ballot, not both")
+	case *fen != "":
+		if b, err = checkers.NewBoardFromFEN(*fen); err != nil {
+			return err
+		}
+	case *ballot != "":
+		bl := checkers.RandomBallot(checkers.BalancedDeck(), rand.New(rand.NewSource(time.Now().UnixNano())))
+		if *ballot != "random" {
+			if bl, err = checkers.ParseBallot(*ballot); err != nil {
+				return err
+			}
+		}
+		fmt.Println("ballot", bl)
+		if b, err = checkers.NewBoardFromBallot(bl); err != nil {
+			return err
+		}
+	}
+	limits := engine.SearchLimits{MoveTime: *moveTime, Depth: *depth, Nodes: *nodes}
+	var clocks map[engine.Engine]*engine.Clock
+	if *tcFlag != "" {
+		tc, err := engine.ParseTimeControl(*tcFlag)
+		if err != nil {
+			return err
+		}
+		clocks = map[engine.Engine]*engine.Clock{red: engine.NewClock(tc), black: engine.NewClock(tc)}
+		limits.MoveTime = 0
+	}
+
+	flagged := ""
+	for !b.Result().IsOver() {
+		fmt.Print(b.BitBoard)
+		p, colour := black, "black"
+		if b.BitBoard.IsRedTurn {
+			p, colour = red, "red"
+		}
+		l := limits
+		if clock := clocks[p]; clock != nil {
+			l = clock.Limits()
+			l.Depth, l.Nodes = limits.Depth, limits.Nodes
+		}
+		start := time.Now()
+		m, err := p.ChooseMove(context.Background(), b, l)
+		if err != nil {
+			return err
+		}
+		if clock := clocks[p]; clock != nil {
+			if !clock.Punch(time.Since(start)) {
+				flagged = colour
+				break
+			}
+			fmt.Printf("%s plays %s (%v left)\n\n", p.Name(), m, clock.Left.Round(time.Millisecond))
+		} else {
+			fmt.Printf("%s plays %s\n\n", p.Name(), m)
+		}
+		m.MakeMove(b)
+		b.PlyCount++
+		if pp, ok := p.(engine.Ponderer); ok && *ponder && isHuman(red, black, b) && !b.Result().IsOver() {
+			pp.Ponder(b)
+		}
+	}
+	for _, p := range []engine.Engine{red, black} {
+		if pp, ok := p.(engine.Ponderer); ok {
+			pp.StopPondering()
+		}
+	}
+	fmt.Print(b.BitBoard)
+	if flagged != "" {
+		fmt.Println(flagged, "lost on time")
+	} else {
+		fmt.Println(b.Result())
+	}
+
+	if *out != "" {
+		game := pdn.FromBoard(b)
+		switch flagged {
+		case "red":
+			game.Result = "0-1"
+		case "black":
+			game.Result = "1-0"
+		}
+		game.SetTag("Result", game.Result)
+		if *tcFlag != "" {
+			game.SetTag("TimeControl", *tcFlag)
+		}
+		game.SetTag("Black", red.Name())
+		game.SetTag("White", black.Name())
+		f, err := os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return pdn.Write(f, game)
+	}
+	return nil
+}
+
+// isHuman reports whether the side to move on b is played by a person.
+func isHuman(red, black engine.Engine, b *checkers.Board) bool {
+	p := black
+	if b.BitBoard.IsRedTurn {
+		p = red
+	}
+	_, ok := p.(*engine.Human)
+	return ok
+}
+
+// consoleInput reads moves typed in square notation, asking again until one is legal.
+func consoleInput(in *bufio.Scanner) func(b *checkers.Board) (checkers.Move, error) {
+	return func(b *checkers.Board) (checkers.Move, error) {
+		for {
+			fmt.Print("move> ")
+			if !in.Scan() {
+				if err := in.Err(); err != nil {
+					return checkers.Move{}, err
+				}
+				return checkers.Move{}, errors.New("no more input")
+			}
+			line := strings.TrimSpace(in.Text())
+			if line == "quit" {
+				return checkers.Move{}, errors.New("quit")
+			}
+			m, err := pdn.ParseMove(b, line)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			return m, nil
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"myproject/checkers"
+)
+
+// BenchPositions is a fixed set of positions, in PDN FEN, searched to a fixed
+// depth to measure how many nodes the search needs. Changes to move ordering
+// or pruning are judged by the total, which only depends on the search and
+// not on the machine. Every position has more than one legal move, since a
+// forced move is played without searching.
+var BenchPositions = []string{
+	"B:W21,22,23,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,10,11,12",
+	"B:W13,19,20,21,25,26,27,28,29,30,31,32:B1,2,3,5,6,7,8,9,10,11,12,18",
+	"W:W18,21,22,23,24,26,27,28,30,31,32:B1,2,3,5,6,7,8,9,10,11,16",
+	"W:W18,19,21,23,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,12,14,20",
+	"W:W17,19,21,22,23,25,26,27,28,29,31,32:B1,2,3,4,5,6,7,10,12,13,16,20",
+	"B:W14,21,22,29,30,31,32:B1,3,5,6,7,11,28",
+	"W:W22,25,K27,30:B5,9,K11,K16",
+	"B:BK1,K6:WK32",
+}
+
+// BenchDepth is the depth the bench searches each position to.
+const BenchDepth = 11
+
+// BenchResult is what the bench found for one position.
+type BenchResult struct {
+	FEN  string
+	Move checkers.Move
+	Info SearchInfo
+}
+
+// Bench searches each of BenchPositions to depth with e, resetting it before
+// each so the counts do not depend on what came before.
+func Bench(ctx context.Context, e Engine, depth int) ([]BenchResult, error) {
+	var results []BenchResult
+	for _, fen := range BenchPositions {
+		b, err := checkers.NewBoardFromFEN(fen)
+		if err != nil {
+			return nil, err
+		}
+		e.Reset()
+		m, err := e.ChooseMove(ctx, b, SearchLimits{Depth: depth})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fen, err)
+		}
+		results = append(results, BenchResult{FEN: fen, Move: m, Info: e.Info()})
+	}
+	return results, nil
+}
@@ -0,0 +1,205 @@
+// Package book holds opening books: for positions early in the game, the moves
+// worth playing and how much weight to give each, so a bot can answer well
+// known openings at once and vary its play between games.
+package book
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+
+	"myproject/checkers"
+)
+
+// Entry is one move of a book position.
+type Entry struct {
+	Move   uint64 // packed with checkers.Move.Pack
+	Weight uint32
+}
+
+// Book maps positions, by their Zobrist hash, to their moves.
+type Book struct {
+	entries map[uint64][]Entry
+}
+
+// New returns an empty book.
+func New() *Book {
+	return &Book{entries: make(map[uint64][]Entry)}
+}
+
+// Len returns the number of positions in the book.
+func (bk *Book) Len() int { return len(bk.entries) }
+
+// Add gives move m from bb the weight w, replacing any it had.
+func (bk *Book) Add(bb *checkers.BitBoard, m checkers.Move, w uint32) {
+	p := m.Pack()
+	es := bk.entries[bb.Hash]
+	for i := range es {
+		if es[i].Move == p {
+			es[i].Weight = w
+			return
+		}
+	}
+	bk.entries[bb.Hash] = append(es, Entry{p, w})
+}
+
+// Choice is a book move with its weight.
+type Choice struct {
+	Move   checkers.Move
+	Weight int
+}
+
+// Choices returns the book moves from b, heaviest first. Entries that are not
+// legal moves in b, which only a hash collision can give, are left out.
+func (bk *Book) Choices(b *checkers.Board) []Choice {
+	if bk == nil {
+		return nil
+	}
+	es := bk.entries[b.BitBoard.Hash]
+	if len(es) == 0 {
+		return nil
+	}
+	var choices []Choice
+	for _, m := range b.GenerateAllMoves() {
+		p := m.Pack()
+		for _, e := range es {
+			if e.Move == p && e.Weight > 0 {
+				choices = append(choices, Choice{m, int(e.Weight)})
+			}
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].Weight > choices[j].Weight })
+	return choices
+}
+
+// Probe picks a book move from b, each with a chance in proportion to its
+// weight, or the heaviest if r is nil. It returns false if b is not in the
+// book.
+func (bk *Book) Probe(b *checkers.Board, r *rand.Rand) (checkers.Move, bool) {
+	choices := bk.Choices(b)
+	if len(choices) == 0 {
+		return checkers.Move{}, false
+	}
+	if r == nil {
+		return choices[0].Move, true
+	}
+	total := 0
+	for _, c := range choices {
+		total += c.Weight
+	}
+	n := r.Intn(total)
+	for _, c := range choices {
+		if n < c.Weight {
+			return c.Move, true
+		}
+		n -= c.Weight
+	}
+	return choices[0].Move, true
+}
+
+// A book file is a header followed by its entries sorted by hash and then
+// move, so equal files come from equal books. Numbers are little endian.
+//
+//	magic   [8]byte  "CKBOOK1\n"
+//	count   uint32   number of entries
+//	count × hash uint64, move uint64, weight uint32
+var magic = [8]byte{'C', 'K', 'B', 'O', 'O', 'K', '1', '\n'}
+
+const entrySize = 20
+
+// WriteTo writes the book in its file format.
+func (bk *Book) WriteTo(w io.Writer) (int64, error) {
+	type record struct {
+		hash uint64
+		Entry
+	}
+	var records []record
+	for h, es := range bk.entries {
+		for _, e := range es {
+			records = append(records, record{h, e})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].hash != records[j].hash {
+			return records[i].hash < records[j].hash
+		}
+		return records[i].Move < records[j].Move
+	})
+
+	bw := bufio.NewWriter(w)
+	buf := append([]byte(nil), magic[:]...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(records)))
+	n, err := bw.Write(buf)
+	written := int64(n)
+	for _, r := range records {
+		if err != nil {
+			break
+		}
+		buf = binary.LittleEndian.AppendUint64(buf[:0], r.hash)
+		buf = binary.LittleEndian.AppendUint64(buf, r.Move)
+		buf = binary.LittleEndian.AppendUint32(buf, r.Weight)
+		n, err = bw.Write(buf)
+		written += int64(n)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	return written, err
+}
+
+var errFormat = errors.New("book: not an opening book")
+
+// Read reads a book written by WriteTo.
+func Read(r io.Reader) (*Book, error) {
+	br := bufio.NewReader(r)
+	head := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(br, head); err != nil || [8]byte(head[:8]) != magic {
+		return nil, errFormat
+	}
+	count := binary.LittleEndian.Uint32(head[8:])
+	bk := New()
+	buf := make([]byte, entrySize)
+	for i := uint32(0); i < count; i++ {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, fmt.Errorf("book: reading entry %d of %d: %w", i+1, count, err)
+		}
+		h := binary.LittleEndian.Uint64(buf)
+		bk.entries[h] = append(bk.entries[h], Entry{
+			Move:   binary.LittleEndian.Uint64(buf[8:]),
+			Weight: binary.LittleEndian.Uint32(buf[16:]),
+		})
+	}
+	return bk, nil
+}
+
+var (
+	openMu sync.Mutex
+	opened = make(map[string]*Book)
+)
+
+// Open reads the book in the named file. Every bot opening the same file
+// shares one copy.
+func Open(path string) (*Book, error) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	if bk, ok := opened[path]; ok {
+		return bk, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	bk, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	opened[path] = bk
+	return bk, nil
+}
@@ -0,0 +1,89 @@
+package book
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"myproject/checkers"
+	"myproject/pdn"
+)
+
+const games = `
+[Event "1"]
+[Result "1-0"]
+1. 11-15 23-19 2. 8-11 22-17 1-0
+
+[Event "2"]
+[Result "1/2-1/2"]
+1. 11-15 24-20 1/2-1/2
+
+[Event "3"]
+[Result "0-1"]
+1. 9-13 22-18 0-1
+
+[Event "4"]
+[Result "1-0"]
+1. 11-15 23-19 1-0
+`
+
+func TestBuild(t *testing.T) {
+	parsed, err := pdn.Parse(games)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bd := NewBuilder(3, 1)
+	for _, g := range parsed {
+		if err := bd.AddGame(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := bd.Book().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	bk, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 11-15 won twice and drew once; 9-13 only lost, so is left out
+	b := checkers.NewBoard()
+	choices := bk.Choices(b)
+	if len(choices) != 1 || choices[0].Move.String() != "11-15" || choices[0].Weight != 5 {
+		t.Fatalf("opening choices %v, want 11-15 weighing 5", choices)
+	}
+	m, _ := bk.Probe(b, nil)
+	m.MakeMove(b)
+
+	// Black drew with 24-20 and lost twice with 23-19
+	choices = bk.Choices(b)
+	if len(choices) != 1 || choices[0].Move.String() != "24-20" || choices[0].Weight != 1 {
+		t.Fatalf("replies %v, want 24-20 weighing 1", choices)
+	}
+	if _, ok := bk.Probe(checkers.NewBoard(), nil); !ok {
+		t.Fatal("the opening is not in the book")
+	}
+}
+
+func TestProbeWeights(t *testing.T) {
+	b := checkers.NewBoard()
+	moves := b.GenerateAllMoves()
+	bk := New()
+	bk.Add(b.BitBoard, moves[0], 3)
+	bk.Add(b.BitBoard, moves[1], 1)
+
+	if m, _ := bk.Probe(b, nil); m.Pack() != moves[0].Pack() {
+		t.Fatalf("without randomness got %s, want the heaviest %s", m, moves[0])
+	}
+	r := rand.New(rand.NewSource(1))
+	first := 0
+	for i := 0; i < 4000; i++ {
+		if m, _ := bk.Probe(b, r); m.Pack() == moves[0].Pack() {
+			first++
+		}
+	}
+	if first < 2800 || first > 3200 {
+		t.Fatalf("the move weighing 3 of 4 was picked %d times in 4000", first)
+	}
+}
@@ -0,0 +1,103 @@
+package book
+
+import (
+	"myproject/checkers"
+	"myproject/pdn"
+)
+
+// Builder gathers the moves played in the first plies of many games and how
+// they turned out, to make a book of them.
+type Builder struct {
+	MaxPlies int // how far into each game moves are counted
+	MinGames int // how often a move must have been played to go in the book
+
+	stats map[uint64]map[uint64]*stat
+}
+
+// stat tallies one move from one position.
+type stat struct {
+	games  int
+	points int // two for each win by the side that played it, one for each draw
+}
+
+// NewBuilder returns a builder counting the first maxPlies plies of each game
+// and keeping moves played at least minGames times.
+func NewBuilder(maxPlies, minGames int) *Builder {
+	return &Builder{MaxPlies: maxPlies, MinGames: minGames, stats: make(map[uint64]map[uint64]*stat)}
+}
+
+// AddBoard counts the moves played on a finished game's board, which ended
+// with status. Games still in progress are skipped.
+func (bd *Builder) AddBoard(game *checkers.Board, status checkers.Status) {
+	if status == checkers.Ongoing {
+		return
+	}
+	b := checkers.NewBoard()
+	*b.BitBoard = game.StartPosition()
+	for i, m := range game.Moves() {
+		if i >= bd.MaxPlies {
+			break
+		}
+		bd.count(b.BitBoard, &m, status)
+		m.MakeMove(b)
+	}
+}
+
+// AddGame counts the moves of the main line of a PDN game.
+func (bd *Builder) AddGame(g *pdn.Game) error {
+	b, err := g.Replay()
+	if err != nil {
+		return err
+	}
+	bd.AddBoard(b, resultStatus(g.Result))
+	return nil
+}
+
+// resultStatus reads a PDN result token, written from red's side.
+func resultStatus(result string) checkers.Status {
+	switch result {
+	case "1-0":
+		return checkers.RedWins
+	case "0-1":
+		return checkers.BlackWins
+	case "1/2-1/2":
+		return checkers.Draw
+	}
+	return checkers.Ongoing
+}
+
+func (bd *Builder) count(bb *checkers.BitBoard, m *checkers.Move, status checkers.Status) {
+	moves := bd.stats[bb.Hash]
+	if moves == nil {
+		moves = make(map[uint64]*stat)
+		bd.stats[bb.Hash] = moves
+	}
+	p := m.Pack()
+	s := moves[p]
+	if s == nil {
+		s = &stat{}
+		moves[p] = s
+	}
+	s.games++
+	switch {
+	case status == checkers.Draw:
+		s.points++
+	case (status == checkers.RedWins) == bb.IsRedTurn:
+		s.points += 2
+	}
+}
+
+// Book returns the book of the moves counted so far that were played often
+// enough, each weighted by the points it scored. A move that only ever lost
+// is left out.
+func (bd *Builder) Book() *Book {
+	bk := New()
+	for h, moves := range bd.stats {
+		for p, s := range moves {
+			if s.games >= bd.MinGames && s.points > 0 {
+				bk.entries[h] = append(bk.entries[h], Entry{p, uint32(s.points)})
+			}
+		}
+	}
+	return bk
+}
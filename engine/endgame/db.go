@@ -0,0 +1,216 @@
+package endgame
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sort"
+	"sync"
+
+	"myproject/checkers"
+)
+
+// Value is the result of a position with best play, for the side to move.
+type Value uint8
+
+const (
+	Unknown Value = iota // not in the database, or not yet worked out
+	Win
+	Loss
+	Draw
+)
+
+func (v Value) String() string {
+	switch v {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	case Draw:
+		return "draw"
+	}
+	return "unknown"
+}
+
+// DB holds a value for every position with up to MaxPieces pieces.
+type DB struct {
+	MaxPieces int
+	tables    map[Signature][]byte // two bits a position, four to a byte
+}
+
+func newDB(maxPieces int) *DB {
+	return &DB{MaxPieces: maxPieces, tables: make(map[Signature][]byte)}
+}
+
+// Signatures returns the material the database covers, smallest first.
+func (db *DB) Signatures() []Signature {
+	var sigs []Signature
+	for s := range db.tables {
+		sigs = append(sigs, s)
+	}
+	sort.Slice(sigs, func(i, j int) bool { return less(sigs[i], sigs[j]) })
+	return sigs
+}
+
+// less orders signatures so that every position's successors after a
+// capture or a crowning come before it: by pieces, then by men.
+func less(a, b Signature) bool {
+	if a.Pieces() != b.Pieces() {
+		return a.Pieces() < b.Pieces()
+	}
+	if am, bm := a.Men+a.OppMen, b.Men+b.OppMen; am != bm {
+		return am < bm
+	}
+	if a.Men != b.Men {
+		return a.Men < b.Men
+	}
+	return a.Kings < b.Kings
+}
+
+// Probe returns the value of bb for the side to move, or false if the
+// database does not cover it.
+func (db *DB) Probe(bb *checkers.BitBoard) (Value, bool) {
+	if db == nil || bits.OnesCount64(bb.Exists) > db.MaxPieces {
+		return Unknown, false
+	}
+	c := canonical(*bb)
+	s := signature(&c)
+	if s.Men+s.Kings == 0 {
+		return Loss, true
+	}
+	if s.OppMen+s.OppKings == 0 {
+		return Win, true
+	}
+	t, ok := db.tables[s]
+	if !ok {
+		return Unknown, false
+	}
+	return get(t, index(&c, s)), true
+}
+
+func get(t []byte, i uint64) Value {
+	return Value(t[i/4]>>(2*(i%4))) & 3
+}
+
+func set(t []byte, i uint64, v Value) {
+	shift := 2 * (i % 4)
+	t[i/4] = t[i/4]&^(3<<shift) | byte(v)<<shift
+}
+
+// The file starts with a header naming the tables it holds, in order, then
+// holds the tables themselves. Numbers are little endian.
+//
+//	magic    [8]byte  "CKEGDB1\n", or "CKEGDTW1" for distances
+//	pieces   uint32   MaxPieces
+//	count    uint32   number of tables
+//	count × [4]byte   signature: men, kings, opponent's men, opponent's kings
+//	tables
+var magic = [8]byte{'C', 'K', 'E', 'G', 'D', 'B', '1', '\n'}
+
+// WriteTo writes the database in its file format, each table packed four
+// positions to a byte.
+func (db *DB) WriteTo(w io.Writer) (int64, error) {
+	return writeTables(w, magic, db.MaxPieces, db.Signatures(), db.tables)
+}
+
+// Read reads a database written by WriteTo.
+func Read(r io.Reader) (*DB, error) {
+	maxPieces, tables, err := readTables(r, magic, func(s Signature) uint64 { return (s.Size() + 3) / 4 })
+	if err != nil {
+		return nil, err
+	}
+	return &DB{MaxPieces: maxPieces, tables: tables}, nil
+}
+
+// Open reads the database in the named file. Every bot opening the same file
+// shares one copy, since a database can be large.
+func Open(path string) (*DB, error) {
+	return open(path, Read)
+}
+
+func writeTables(w io.Writer, magic [8]byte, maxPieces int, sigs []Signature, tables map[Signature][]byte) (int64, error) {
+	bw := bufio.NewWriter(w)
+	header := append([]byte(nil), magic[:]...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(maxPieces))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(sigs)))
+	for _, s := range sigs {
+		header = append(header, byte(s.Men), byte(s.Kings), byte(s.OppMen), byte(s.OppKings))
+	}
+	n, err := bw.Write(header)
+	written := int64(n)
+	for _, s := range sigs {
+		if err != nil {
+			break
+		}
+		n, err = bw.Write(tables[s])
+		written += int64(n)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	return written, err
+}
+
+var errFormat = errors.New("endgame: not an endgame database")
+
+// readTables reads a file written by writeTables, in which each table takes
+// size bytes.
+func readTables(r io.Reader, magic [8]byte, size func(Signature) uint64) (int, map[Signature][]byte, error) {
+	br := bufio.NewReader(r)
+	var head struct {
+		Magic  [8]byte
+		Pieces uint32
+		Count  uint32
+	}
+	if err := binary.Read(br, binary.LittleEndian, &head); err != nil || head.Magic != magic {
+		return 0, nil, errFormat
+	}
+	sigs := make([][4]byte, head.Count)
+	if err := binary.Read(br, binary.LittleEndian, sigs); err != nil {
+		return 0, nil, errFormat
+	}
+	maxPieces := int(head.Pieces)
+	tables := make(map[Signature][]byte)
+	for _, b := range sigs {
+		s := Signature{int(b[0]), int(b[1]), int(b[2]), int(b[3])}
+		if s.Pieces() > maxPieces {
+			return 0, nil, fmt.Errorf("endgame: table %s has more than %d pieces", s, maxPieces)
+		}
+		t := make([]byte, size(s))
+		if _, err := io.ReadFull(br, t); err != nil {
+			return 0, nil, fmt.Errorf("endgame: reading table %s: %w", s, err)
+		}
+		tables[s] = t
+	}
+	return maxPieces, tables, nil
+}
+
+var (
+	openMu sync.Mutex
+	opened = make(map[string]any)
+)
+
+// open reads the named file with read, or returns the copy read already.
+func open[T any](path string, read func(io.Reader) (T, error)) (T, error) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	if v, ok := opened[path].(T); ok {
+		return v, nil
+	}
+	var zero T
+	f, err := os.Open(path)
+	if err != nil {
+		return zero, err
+	}
+	defer f.Close()
+	v, err := read(f)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", path, err)
+	}
+	opened[path] = v
+	return v, nil
+}
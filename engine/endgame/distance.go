@@ -0,0 +1,336 @@
+package endgame
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+
+	"myproject/checkers"
+)
+
+// maxDistance is the furthest a game can be from its end and still fit in a
+// table: each position takes a byte holding its distance plus one, with 0
+// for a draw.
+const maxDistance = 254
+
+// Distances holds, for every position with up to MaxPieces pieces, how many
+// plies the game lasts with best play: the winner ending it as soon as it
+// can and the loser holding out as long as it can. A position the side to
+// move has won is an odd number of plies from the end, since the winner
+// makes the last move, and one it has lost an even number. Draws have no
+// distance. The tables take no account of the rule drawing a game after long
+// play without progress.
+type Distances struct {
+	MaxPieces int
+	tables    map[Signature][]byte
+}
+
+// Signatures returns the material the tables cover, smallest first.
+func (t *Distances) Signatures() []Signature {
+	var sigs []Signature
+	for s := range t.tables {
+		sigs = append(sigs, s)
+	}
+	sort.Slice(sigs, func(i, j int) bool { return less(sigs[i], sigs[j]) })
+	return sigs
+}
+
+// Probe returns the value of bb for the side to move and, unless it is a
+// draw, the number of plies to the end of the game, or false if the tables
+// do not cover it.
+func (t *Distances) Probe(bb *checkers.BitBoard) (Value, int, bool) {
+	if t == nil || bits.OnesCount64(bb.Exists) > t.MaxPieces {
+		return Unknown, 0, false
+	}
+	c := canonical(*bb)
+	s := signature(&c)
+	if s.Men+s.Kings == 0 {
+		return Loss, 0, true
+	}
+	if s.OppMen+s.OppKings == 0 {
+		// The opponent lost with the last move, which cannot happen with
+		// this side to move
+		return Win, 1, true
+	}
+	tab, ok := t.tables[s]
+	if !ok {
+		return Unknown, 0, false
+	}
+	v, d := distanceValue(tab[index(&c, s)])
+	return v, d, true
+}
+
+// distanceValue decodes a table entry.
+func distanceValue(e byte) (Value, int) {
+	switch {
+	case e == 0:
+		return Draw, 0
+	case (e-1)%2 == 1:
+		return Win, int(e - 1)
+	}
+	return Loss, int(e - 1)
+}
+
+// BestMove returns the move that wins quickest from b, or holds out longest
+// if b is lost, or keeps a draw, with the value of b for the side to move and
+// its distance from the end. It returns false if the tables do not cover b or
+// there is no move to make.
+func (t *Distances) BestMove(b *checkers.Board) (checkers.Move, Value, int, bool) {
+	v, d, ok := t.Probe(b.BitBoard)
+	if !ok {
+		return checkers.Move{}, Unknown, 0, false
+	}
+	moves := b.GenerateAllMoves()
+	for _, m := range moves {
+		child := apply(*b.BitBoard, &m)
+		cv, cd, ok := t.Probe(&child)
+		if !ok {
+			continue
+		}
+		switch {
+		case v == Win && cv == Loss && cd == d-1,
+			v == Loss && cv == Win && cd == d-1,
+			v == Draw && cv == Draw:
+			return m, v, d, true
+		}
+	}
+	return checkers.Move{}, v, d, false
+}
+
+// GenerateDistances works out the distance of every position with up to
+// maxPieces pieces. It first finds which are won and lost as Generate does,
+// then settles their distances in order: those ending the game now, then those
+// one ply from the end, and so on. It fails if a game lasts too long for the
+// tables to hold. If progress is not nil it is called as each table is
+// finished.
+func GenerateDistances(maxPieces int, progress func(s Signature, c Counts)) (*Distances, error) {
+	db := newDB(maxPieces)
+	t := &Distances{MaxPieces: maxPieces, tables: make(map[Signature][]byte)}
+	longest := 0 // the furthest from the end of any finished table
+	for _, s := range signatures(maxPieces) {
+		if _, done := t.tables[s]; done {
+			continue
+		}
+		group := []Signature{s}
+		if m := s.Mirror(); m != s {
+			group = append(group, m)
+		}
+		sv := newSolver(db, group)
+		sv.solve()
+		ds := &distanceSolver{t: t, values: sv.work, work: make(map[Signature][]byte)}
+		for _, s := range group {
+			ds.work[s] = make([]byte, s.Size())
+		}
+		d, err := ds.solve(longest)
+		if err != nil {
+			return nil, err
+		}
+		longest = max(longest, d)
+		for _, s := range group {
+			var c Counts
+			tab := make([]byte, (s.Size()+3)/4)
+			for i, v := range sv.work[s] {
+				set(tab, uint64(i), v)
+				c.add(v)
+			}
+			db.tables[s] = tab
+			t.tables[s] = ds.work[s]
+			if progress != nil {
+				progress(s, c)
+			}
+		}
+	}
+	return t, nil
+}
+
+// distanceSolver settles the distances of the won and lost positions in a
+// group of tables whose values are known.
+type distanceSolver struct {
+	t      *Distances
+	values map[Signature][]Value
+	work   map[Signature][]byte
+	board  checkers.Board
+	moves  []checkers.Move
+}
+
+// solve settles a ply of distance a pass, a position being settled in the
+// pass for its distance once its successors are. Positions further from the
+// end than the furthest in a smaller table only follow ones in the group, so
+// a pass past that which settles nothing is the last. It returns the
+// furthest distance found.
+func (ds *distanceSolver) solve(longest int) (int, error) {
+	pending := make(map[Signature][]uint32)
+	for s, values := range ds.values {
+		var list []uint32
+		for i, v := range values {
+			if v == Win || v == Loss {
+				list = append(list, uint32(i))
+			}
+		}
+		pending[s] = list
+	}
+	furthest := 0
+	for d := 0; ; d++ {
+		settled := false
+		for s, list := range pending {
+			kept := list[:0]
+			for _, i := range list {
+				bb, _ := position(uint64(i), s)
+				if ds.settles(bb, ds.values[s][i], d) {
+					if d > maxDistance {
+						return 0, fmt.Errorf("endgame: %s is more than %d plies from the end", bb.FEN(), maxDistance)
+					}
+					ds.work[s][i] = byte(d + 1)
+					settled = true
+				} else {
+					kept = append(kept, i)
+				}
+			}
+			pending[s] = kept
+		}
+		if settled {
+			furthest = d
+			continue
+		}
+		if d <= longest {
+			continue
+		}
+		for s, list := range pending {
+			if len(list) > 0 {
+				bb, _ := position(uint64(list[0]), s)
+				return 0, fmt.Errorf("endgame: %s is a %s with no distance", bb.FEN(), ds.values[s][list[0]])
+			}
+		}
+		return furthest, nil
+	}
+}
+
+// settles reports whether bb, which has red to move and the value v, is d
+// plies from the end, given that every position nearer the end is settled. A
+// win is one ply further than its nearest lost successor, and a loss one
+// further than its furthest successor, all of which are won.
+func (ds *distanceSolver) settles(bb checkers.BitBoard, v Value, d int) bool {
+	ds.board.BitBoard = &bb
+	ds.moves = ds.board.AppendMoves(ds.moves[:0])
+	longest := -1
+	for i := range ds.moves {
+		cv, cd, ok := ds.lookup(apply(bb, &ds.moves[i]))
+		switch {
+		case v == Win:
+			if ok && cv == Loss && cd == d-1 {
+				return true
+			}
+		case !ok:
+			return false // a successor further from the end than d-1
+		default:
+			longest = max(longest, cd)
+		}
+	}
+	return v == Loss && longest == d-1
+}
+
+// lookup returns the value and distance of a position reached by a move, or
+// false if it is in the group and not settled yet.
+func (ds *distanceSolver) lookup(bb checkers.BitBoard) (Value, int, bool) {
+	c := canonical(bb)
+	s := signature(&c)
+	if s.Men+s.Kings == 0 {
+		return Loss, 0, true
+	}
+	w, ok := ds.work[s]
+	if !ok {
+		v, d := distanceValue(ds.t.tables[s][index(&c, s)])
+		return v, d, true
+	}
+	i := index(&c, s)
+	if ds.values[s][i] == Draw {
+		return Draw, 0, true
+	}
+	if w[i] == 0 {
+		return Unknown, 0, false
+	}
+	v, d := distanceValue(w[i])
+	return v, d, true
+}
+
+// WriteTo writes the tables in the file format of a DB, each position taking
+// a byte.
+func (t *Distances) WriteTo(w io.Writer) (int64, error) {
+	return writeTables(w, distanceMagic, t.MaxPieces, t.Signatures(), t.tables)
+}
+
+var distanceMagic = [8]byte{'C', 'K', 'E', 'G', 'D', 'T', 'W', '1'}
+
+// ReadDistances reads tables written by Distances.WriteTo.
+func ReadDistances(r io.Reader) (*Distances, error) {
+	maxPieces, tables, err := readTables(r, distanceMagic, Signature.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &Distances{MaxPieces: maxPieces, tables: tables}, nil
+}
+
+// OpenDistances reads the tables in the named file, sharing one copy as Open
+// does.
+func OpenDistances(path string) (*Distances, error) {
+	return open(path, ReadDistances)
+}
+
+// VerifyDistances checks that the distance of every position in t follows
+// from those of the positions its moves lead to. If progress is not nil it is
+// called as each table is checked.
+func VerifyDistances(t *Distances, progress func(s Signature, c Counts)) error {
+	var board checkers.Board
+	var moves []checkers.Move
+	for _, s := range t.Signatures() {
+		if _, ok := t.tables[s.Mirror()]; !ok {
+			return fmt.Errorf("endgame: table %s has no mirror image %s", s, s.Mirror())
+		}
+		var c Counts
+		for i := uint64(0); i < s.Size(); i++ {
+			bb, ok := position(i, s)
+			if !ok {
+				continue
+			}
+			v, d := distanceValue(t.tables[s][i])
+			board.BitBoard = &bb
+			moves = board.AppendMoves(moves[:0])
+			// The quickest loss for the opponent and the slowest win, and
+			// whether any successor is drawn
+			quickest, slowest, drawn := -1, -1, false
+			for j := range moves {
+				child := apply(bb, &moves[j])
+				cv, cd, _ := t.Probe(&child)
+				switch cv {
+				case Loss:
+					if quickest < 0 || cd < quickest {
+						quickest = cd
+					}
+				case Win:
+					slowest = max(slowest, cd)
+				default:
+					drawn = true
+				}
+			}
+			var want Value
+			wantD := 0
+			switch {
+			case quickest >= 0:
+				want, wantD = Win, quickest+1
+			case drawn:
+				want = Draw
+			default:
+				want, wantD = Loss, slowest+1
+			}
+			if v != want || d != wantD {
+				return fmt.Errorf("endgame: %s: %s is stored as a %s in %d but its moves make it a %s in %d", s, bb.FEN(), v, d, want, wantD)
+			}
+			c.add(v)
+		}
+		if progress != nil {
+			progress(s, c)
+		}
+	}
+	return nil
+}
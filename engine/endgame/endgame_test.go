@@ -0,0 +1,93 @@
+package endgame
+
+import (
+	"bytes"
+	"testing"
+
+	"myproject/checkers"
+)
+
+func TestIndexRoundTrip(t *testing.T) {
+	for _, s := range []Signature{{1, 0, 1, 0}, {0, 2, 1, 0}, {2, 1, 0, 1}, {1, 1, 1, 1}} {
+		for i := uint64(0); i < s.Size(); i += 1 + s.Size()/5000 {
+			bb, ok := position(i, s)
+			if !ok {
+				continue
+			}
+			if got := signature(&bb); got != s {
+				t.Fatalf("%s: position %d has material %s", s, i, got)
+			}
+			if got := index(&bb, s); got != i {
+				t.Fatalf("%s: position %d indexes as %d", s, i, got)
+			}
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	db := Generate(3, nil)
+	if err := Verify(db, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := db.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Signatures()) != len(db.Signatures()) {
+		t.Fatalf("read %d tables, wrote %d", len(read.Signatures()), len(db.Signatures()))
+	}
+
+	for _, tt := range []struct {
+		fen  string
+		want Value
+	}{
+		{"B:WK1:BK27,K32", Win},  // two kings against one
+		{"W:WK1:BK27,K32", Loss}, // the same, the lone king to move
+		{"W:WK1,K5:BK32", Win},   // the same with the colours swapped
+		{"B:W29:B4", Draw},       // a man each, neither able to get at the other
+	} {
+		b, err := checkers.NewBoardFromFEN(tt.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := read.Probe(b.BitBoard); !ok || got != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.fen, got, ok, tt.want)
+		}
+	}
+}
+
+func TestDistances(t *testing.T) {
+	dist, err := GenerateDistances(3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyDistances(dist, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Playing the best move throughout takes exactly as long as the tables say
+	b, err := checkers.NewBoardFromFEN("B:WK1:BK27,K32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, d, ok := dist.Probe(b.BitBoard)
+	if !ok || v != Win {
+		t.Fatalf("got %s, %v, want a win", v, ok)
+	}
+	for plies := 0; ; plies++ {
+		m, _, _, ok := dist.BestMove(b)
+		if !ok {
+			if plies != d || !b.Result().IsOver() {
+				t.Fatalf("game stopped after %d plies, want %d: %s", plies, d, b.BitBoard.FEN())
+			}
+			break
+		}
+		m.MakeMove(b)
+		b.PlyCount++
+	}
+}
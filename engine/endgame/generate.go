@@ -0,0 +1,345 @@
+package endgame
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"myproject/checkers"
+)
+
+// Counts tallies the values in a table.
+type Counts struct {
+	Wins, Losses, Draws int
+}
+
+func (c Counts) String() string {
+	return fmt.Sprintf("%d wins, %d losses, %d draws", c.Wins, c.Losses, c.Draws)
+}
+
+func (c *Counts) add(v Value) {
+	switch v {
+	case Win:
+		c.Wins++
+	case Loss:
+		c.Losses++
+	case Draw:
+		c.Draws++
+	}
+}
+
+// Generate works out the value of every position with up to maxPieces pieces.
+// A table depends on those reached from it by a capture or a crowning, so they
+// are done first. A table and its mirror image, the same material with the
+// other side to move, are worked out together, since ordinary moves lead from
+// each into the other. If progress is not nil it is called as each table is
+// finished.
+func Generate(maxPieces int, progress func(s Signature, c Counts)) *DB {
+	db := newDB(maxPieces)
+	for _, s := range signatures(maxPieces) {
+		if _, done := db.tables[s]; done {
+			continue
+		}
+		group := []Signature{s}
+		if m := s.Mirror(); m != s {
+			group = append(group, m)
+		}
+		sv := newSolver(db, group)
+		sv.solve()
+		for _, s := range group {
+			var c Counts
+			t := make([]byte, (s.Size()+3)/4)
+			for i, v := range sv.work[s] {
+				set(t, uint64(i), v)
+				c.add(v)
+			}
+			db.tables[s] = t
+			if progress != nil {
+				progress(s, c)
+			}
+		}
+	}
+	return db
+}
+
+// signatures lists every material with pieces on both sides and no more than
+// maxPieces in all, in the order they can be worked out.
+func signatures(maxPieces int) []Signature {
+	var sigs []Signature
+	for n := 2; n <= maxPieces; n++ {
+		for men := 0; men <= n; men++ {
+			for kings := 0; men+kings <= n; kings++ {
+				for oppMen := 0; men+kings+oppMen <= n; oppMen++ {
+					s := Signature{men, kings, oppMen, n - men - kings - oppMen}
+					if s.Men+s.Kings > 0 && s.OppMen+s.OppKings > 0 && s.Men <= 12 && s.OppMen <= 12 {
+						sigs = append(sigs, s)
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(sigs, func(i, j int) bool { return less(sigs[i], sigs[j]) })
+	return sigs
+}
+
+// solver works out a group of tables by retrograde analysis. Every position
+// starts with a count of its moves that stay in the group, and is settled at
+// once if the moves out of it decide it: a capture or a crowning reaching a
+// lost position makes it a win, and having no move at all a loss. From each
+// settled position the solver steps back to the positions that lead to it by
+// an ordinary move: a move to a loss wins, and a move to a win takes one off
+// the count, which at nought leaves nothing but wins for the opponent. A move
+// out of the group to a draw keeps the count above nought, and whatever is
+// left unsettled at the end is drawn, since neither side can force a result.
+type solver struct {
+	db    *DB
+	work  map[Signature][]Value
+	board checkers.Board
+	moves []checkers.Move
+}
+
+func newSolver(db *DB, group []Signature) *solver {
+	sv := &solver{db: db, work: make(map[Signature][]Value)}
+	for _, s := range group {
+		sv.work[s] = make([]Value, s.Size())
+	}
+	return sv
+}
+
+// settled is a position whose value is known but not yet passed back to the
+// positions before it.
+type settled struct {
+	s Signature
+	i uint64
+}
+
+func (sv *solver) solve() {
+	counts := make(map[Signature][]uint8)
+	var queue []settled
+	for s, values := range sv.work {
+		counts[s] = make([]uint8, len(values))
+		for i := range values {
+			bb, ok := position(uint64(i), s)
+			if !ok {
+				continue
+			}
+			values[i] = sv.start(bb, &counts[s][i])
+			if values[i] == Win || values[i] == Loss {
+				queue = append(queue, settled{s, uint64(i)})
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		v := sv.work[x.s][x.i]
+		bb, _ := position(x.i, x.s)
+		sv.unmoves(bb, func(p checkers.BitBoard) {
+			c := canonical(p)
+			s := signature(&c)
+			i := index(&c, s)
+			if sv.work[s][i] != Unknown {
+				return
+			}
+			if v == Win {
+				if counts[s][i]--; counts[s][i] > 0 {
+					return
+				}
+				sv.work[s][i] = Loss
+			} else {
+				sv.work[s][i] = Win
+			}
+			queue = append(queue, settled{s, i})
+		})
+	}
+
+	for s, values := range sv.work {
+		for i, v := range values {
+			if v == Unknown && counts[s][i] > 0 {
+				values[i] = Draw
+			}
+		}
+	}
+}
+
+// start looks at the moves from bb, which has red to move. It returns the
+// value of bb if the moves out of the group settle it, and otherwise sets
+// count to the number of moves to be settled, one more if a move out of the
+// group draws.
+func (sv *solver) start(bb checkers.BitBoard, count *uint8) Value {
+	sv.board.BitBoard = &bb
+	sv.moves = sv.board.AppendMoves(sv.moves[:0])
+	n, draw := 0, false
+	for i := range sv.moves {
+		c := canonical(apply(bb, &sv.moves[i]))
+		s := signature(&c)
+		if _, ok := sv.work[s]; ok {
+			n++
+			continue
+		}
+		if s.Men+s.Kings == 0 {
+			return Win
+		}
+		switch get(sv.db.tables[s], index(&c, s)) {
+		case Loss:
+			return Win
+		case Draw:
+			draw = true
+		}
+	}
+	switch {
+	case n == 0 && draw:
+		return Draw
+	case n == 0:
+		return Loss
+	case draw:
+		n++
+	}
+	*count = uint8(n)
+	return Unknown
+}
+
+// unmoves calls f with each position, black to move, from which black reaches
+// bb by an ordinary move: one that neither captures nor crowns. A position in
+// which black had a capture is left out, since black would have had to take.
+func (sv *solver) unmoves(bb checkers.BitBoard, f func(checkers.BitBoard)) {
+	black := bb.Exists &^ bb.Red
+	for pieces := black; pieces != 0; pieces &= pieces - 1 {
+		to := bits.TrailingZeros64(pieces)
+		from := manSteps[to]
+		if bb.King&(uint64(1)<<to) != 0 {
+			from = kingSteps[to]
+		}
+		for from &^= bb.Exists; from != 0; from &= from - 1 {
+			p := bb
+			move(&p, uint64(1)<<to, from&-from)
+			p.IsRedTurn = false
+			sv.board.BitBoard = &p
+			if sv.moves = sv.board.AppendMoves(sv.moves[:0]); len(sv.moves) > 0 && sv.moves[0].IsJump {
+				continue
+			}
+			f(p)
+		}
+	}
+}
+
+// move shifts the piece on square to onto the empty square from.
+func move(bb *checkers.BitBoard, to, from uint64) {
+	bb.Exists ^= to | from
+	if bb.Red&to != 0 {
+		bb.Red ^= to | from
+	}
+	if bb.King&to != 0 {
+		bb.King ^= to | from
+	}
+}
+
+// The squares a piece could have stepped to each square from: any neighbour
+// for a king, and for a black man, which goes down the board, those above.
+var kingSteps, manSteps [64]uint64
+
+func init() {
+	for sq := 0; sq < 64; sq++ {
+		if checkers.Playable&(uint64(1)<<sq) == 0 {
+			continue
+		}
+		x, y := sq%8, sq/8
+		for _, d := range [][2]int{{1, 1}, {-1, 1}, {1, -1}, {-1, -1}} {
+			fx, fy := x+d[0], y+d[1]
+			if fx < 0 || fx >= checkers.BoardSize || fy < 0 || fy >= checkers.BoardSize {
+				continue
+			}
+			kingSteps[sq] |= uint64(1) << (fx + fy*8)
+			if d[1] < 0 {
+				manSteps[sq] |= uint64(1) << (fx + fy*8)
+			}
+		}
+	}
+}
+
+// evaluate works out the value of bb, which has red to move, from the values
+// lookup gives the positions its moves lead to. It returns Unknown if they do
+// not settle it yet.
+func (sv *solver) evaluate(bb checkers.BitBoard, lookup func(checkers.BitBoard) Value) Value {
+	sv.board.BitBoard = &bb
+	sv.moves = sv.board.AppendMoves(sv.moves[:0])
+	unknown, draw := false, false
+	for i := range sv.moves {
+		switch lookup(apply(bb, &sv.moves[i])) {
+		case Loss:
+			return Win
+		case Unknown:
+			unknown = true
+		case Draw:
+			draw = true
+		}
+	}
+	switch {
+	case unknown:
+		return Unknown
+	case draw:
+		return Draw
+	}
+	return Loss
+}
+
+// apply returns the position after m is played on bb. Unlike
+// checkers.Move.MakeMove it keeps no history and leaves the hash alone.
+func apply(bb checkers.BitBoard, m *checkers.Move) checkers.BitBoard {
+	from := uint64(1) << (m.FromX + m.FromY*8)
+	to := uint64(1) << (m.ToX + m.ToY*8)
+	red := bb.Red&from != 0
+	king := bb.King&from != 0 || m.Promotes()
+	gone := from | m.Captured
+	bb.Exists &^= gone
+	bb.Red &^= gone
+	bb.King &^= gone
+	bb.Exists |= to
+	if red {
+		bb.Red |= to
+	}
+	if king {
+		bb.King |= to
+	}
+	bb.IsRedTurn = !bb.IsRedTurn
+	return bb
+}
+
+// Verify checks that the value of every position in db follows from the
+// values of the positions its moves lead to: a win must have a move to a
+// loss, a loss only moves to wins, and a draw neither. If progress is not nil
+// it is called as each table is checked.
+func Verify(db *DB, progress func(s Signature, c Counts)) error {
+	sv := &solver{db: db}
+	lookup := func(bb checkers.BitBoard) Value {
+		v, _ := db.Probe(&bb)
+		return v
+	}
+	for _, s := range db.Signatures() {
+		if s.Pieces() > db.MaxPieces {
+			return fmt.Errorf("endgame: table %s has more than %d pieces", s, db.MaxPieces)
+		}
+		if _, ok := db.tables[s.Mirror()]; !ok {
+			return fmt.Errorf("endgame: table %s has no mirror image %s", s, s.Mirror())
+		}
+		var c Counts
+		t := db.tables[s]
+		for i := uint64(0); i < s.Size(); i++ {
+			bb, ok := position(i, s)
+			if !ok {
+				continue
+			}
+			got := get(t, i)
+			want := sv.evaluate(bb, lookup)
+			if got != want {
+				return fmt.Errorf("endgame: %s: %s is stored as a %s but its moves make it a %s", s, bb.FEN(), got, want)
+			}
+			c.add(got)
+		}
+		if progress != nil {
+			progress(s, c)
+		}
+	}
+	return nil
+}
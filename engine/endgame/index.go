@@ -0,0 +1,159 @@
+// Package endgame builds and probes win/loss/draw databases of every position
+// with few enough pieces, found by retrograde analysis rather than search.
+//
+// Positions are always looked at from the side to move: one with black to
+// move is turned round and its colours swapped so that red is to move, which
+// halves the number to store. The material on the board, from the mover's
+// side, picks a table, and the squares of the pieces an index within it.
+package endgame
+
+import (
+	"fmt"
+	"math/bits"
+
+	"myproject/checkers"
+)
+
+// Signature is the material in a position, the mover's pieces first.
+type Signature struct {
+	Men, Kings       int
+	OppMen, OppKings int
+}
+
+func (s Signature) String() string {
+	return fmt.Sprintf("%dm%dk-%dm%dk", s.Men, s.Kings, s.OppMen, s.OppKings)
+}
+
+// Pieces returns the number of pieces on the board.
+func (s Signature) Pieces() int { return s.Men + s.Kings + s.OppMen + s.OppKings }
+
+// Mirror returns the signature seen from the other side.
+func (s Signature) Mirror() Signature {
+	return Signature{s.OppMen, s.OppKings, s.Men, s.Kings}
+}
+
+// Size returns the number of indexes in the signature's table. Some of them
+// put two pieces on one square and stand for no position.
+func (s Signature) Size() uint64 {
+	return binom[len(ownManSquares)][s.Men] * binom[len(oppManSquares)][s.OppMen] *
+		binom[32][s.Kings] * binom[32][s.OppKings]
+}
+
+var (
+	binom [33][33]uint64
+
+	// The squares pieces may stand on, as bit indexes x+y*8. The mover's men
+	// go up the board and are crowned on the top row, so never stand there;
+	// the opponent's men never stand on the bottom row.
+	allSquares    []int
+	ownManSquares []int
+	oppManSquares []int
+)
+
+func init() {
+	for n := range binom {
+		binom[n][0] = 1
+		for k := 1; k <= n; k++ {
+			binom[n][k] = binom[n-1][k-1] + binom[n-1][k]
+		}
+	}
+	for sq := 0; sq < 64; sq++ {
+		if checkers.Playable&(uint64(1)<<sq) == 0 {
+			continue
+		}
+		allSquares = append(allSquares, sq)
+		if sq/8 != 0 {
+			ownManSquares = append(ownManSquares, sq)
+		}
+		if sq/8 != checkers.BoardSize-1 {
+			oppManSquares = append(oppManSquares, sq)
+		}
+	}
+}
+
+// canonical returns bb with red to move, turning the board round and swapping
+// the colours if black is to move.
+func canonical(bb checkers.BitBoard) checkers.BitBoard {
+	if bb.IsRedTurn {
+		return bb
+	}
+	return checkers.BitBoard{
+		Exists:    bits.Reverse64(bb.Exists),
+		Red:       bits.Reverse64(bb.Exists &^ bb.Red),
+		King:      bits.Reverse64(bb.King),
+		IsRedTurn: true,
+	}
+}
+
+// signature returns the material of bb, which has red to move.
+func signature(bb *checkers.BitBoard) Signature {
+	own, opp := bb.Exists&bb.Red, bb.Exists&^bb.Red
+	return Signature{
+		Men:      bits.OnesCount64(own &^ bb.King),
+		Kings:    bits.OnesCount64(own & bb.King),
+		OppMen:   bits.OnesCount64(opp &^ bb.King),
+		OppKings: bits.OnesCount64(opp & bb.King),
+	}
+}
+
+// rank returns the index of the set of pieces among the squares in the
+// combinatorial number system.
+func rank(pieces uint64, squares []int) uint64 {
+	var r uint64
+	k := 0
+	for i, sq := range squares {
+		if pieces&(uint64(1)<<sq) != 0 {
+			k++
+			r += binom[i][k]
+		}
+	}
+	return r
+}
+
+// unrank is the inverse of rank for a set of k pieces.
+func unrank(r uint64, k int, squares []int) uint64 {
+	var pieces uint64
+	for i := len(squares) - 1; k > 0; i-- {
+		if b := binom[i][k]; r >= b {
+			r -= b
+			pieces |= uint64(1) << squares[i]
+			k--
+		}
+	}
+	return pieces
+}
+
+// index returns the index of bb, which has red to move, in its signature's table.
+func index(bb *checkers.BitBoard, s Signature) uint64 {
+	own, opp := bb.Exists&bb.Red, bb.Exists&^bb.Red
+	i := rank(own&^bb.King, ownManSquares)
+	i = i*binom[len(oppManSquares)][s.OppMen] + rank(opp&^bb.King, oppManSquares)
+	i = i*binom[32][s.Kings] + rank(own&bb.King, allSquares)
+	i = i*binom[32][s.OppKings] + rank(opp&bb.King, allSquares)
+	return i
+}
+
+// position returns the position with red to move at index i of s's table, or
+// false if i puts two pieces on one square.
+func position(i uint64, s Signature) (checkers.BitBoard, bool) {
+	n := binom[32][s.OppKings]
+	oppKings := unrank(i%n, s.OppKings, allSquares)
+	i /= n
+	n = binom[32][s.Kings]
+	kings := unrank(i%n, s.Kings, allSquares)
+	i /= n
+	n = binom[len(oppManSquares)][s.OppMen]
+	oppMen := unrank(i%n, s.OppMen, oppManSquares)
+	men := unrank(i/n, s.Men, ownManSquares)
+
+	all := men | kings | oppMen | oppKings
+	if bits.OnesCount64(all) != s.Pieces() {
+		return checkers.BitBoard{}, false
+	}
+	return checkers.BitBoard{
+		Exists:    all,
+		Red:       men | kings,
+		King:      kings | oppKings,
+		IsRedTurn: true,
+	}, true
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"myproject/checkers"
+)
+
+// Engine is anything that can play a side: a search bot or a person.
+type Engine interface {
+	// Name identifies the player in logs and PDN tags.
+	Name() string
+	// ChooseMove picks a move for the side to move on b without playing it.
+	// b is left as it was found. Cancelling ctx stops the search, and the best
+	// move found so far is returned.
+	ChooseMove(ctx context.Context, b *checkers.Board, limits SearchLimits) (checkers.Move, error)
+	// Info describes the last search.
+	Info() SearchInfo
+	// Reset forgets everything learnt so far, ready for a new game.
+	Reset()
+}
+
+// Threaded is implemented by engines that can search on several threads at once.
+type Threaded interface {
+	SetThreads(n int)
+}
+
+// Configurable is implemented by engines with options that change how they
+// play, so that versions of one engine can be matched against each other.
+type Configurable interface {
+	SetOption(name, value string) error
+}
+
+// Ponderer is implemented by engines that can think on the opponent's time.
+type Ponderer interface {
+	// Ponder starts searching in the background on b, where the opponent is
+	// to move, guessing their reply. The next ChooseMove picks the search up
+	// if the guess was right. b is not kept.
+	Ponder(b *checkers.Board)
+	// StopPondering ends any pondering and waits for it to wind up.
+	StopPondering()
+}
+
+// SearchLimits bounds how long an engine may think about one move. A zero
+// field sets no limit; the search stops at whichever set limit comes first.
+type SearchLimits struct {
+	Depth     int           // deepest iteration to search
+	Nodes     int           // positions to visit
+	MoveTime  time.Duration // time for this move
+	Clock     time.Duration // time left on the mover's clock
+	Increment time.Duration // time added to the clock after each move
+	MovesToGo int           // moves left until the clock is topped up, 0 if it never is
+	Infinite  bool          // search until cancelled, ignoring the other limits
+
+	// MultiPV is how many of the best root moves to rank, each with its own
+	// score and line, for analysis. Zero or one searches for the best only.
+	MultiPV int
+}
+
+var DefaultLimits = SearchLimits{MoveTime: time.Second, Nodes: 3_000_000}
+
+// WithDefaults returns DefaultLimits, keeping MultiPV, if l sets no limit at all.
+func (l SearchLimits) WithDefaults() SearchLimits {
+	if l == (SearchLimits{MultiPV: l.MultiPV}) {
+		d := DefaultLimits
+		d.MultiPV = l.MultiPV
+		return d
+	}
+	return l
+}
+
+// NodeBudget returns the number of nodes the search may visit.
+func (l SearchLimits) NodeBudget() int {
+	if l.Infinite || l.Nodes == 0 {
+		return math.MaxInt
+	}
+	return l.Nodes
+}
+
+// MaxDepth returns the deepest iteration allowed, or 0 for no limit.
+func (l SearchLimits) MaxDepth() int {
+	if l.Infinite {
+		return 0
+	}
+	return l.Depth
+}
+
+// SearchInfo is what an engine reports about its last search.
+type SearchInfo struct {
+	Depth    int
+	SelDepth int     // the deepest ply reached, counting the capture search
+	Score    float64 // from the point of view of the side to move
+	Nodes    int
+	NPS      int // nodes per second
+	Hashfull int // permille of the transposition table in use
+	Time     time.Duration
+	PV       []checkers.Move // the best line, starting with the move chosen
+	Lines    []Line          // the best root moves ranked, Lines[0] being Score and PV
+}
+
+var ErrGameOver = errors.New("engine: the game is over")
+
+// ErrUnknownOption is wrapped by the error SetOption returns for an option
+// the engine does not have.
+var ErrUnknownOption = errors.New("unknown option")
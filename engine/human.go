@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"myproject/checkers"
+)
+
+// Human is a player whose moves come from a person. Input is asked for a move
+// each turn; the GUI feeds it from mouse clicks and the CLI from the terminal.
+type Human struct {
+	Input func(b *checkers.Board) (checkers.Move, error)
+}
+
+func NewHuman(input func(b *checkers.Board) (checkers.Move, error)) *Human {
+	return &Human{Input: input}
+}
+
+func (h *Human) Name() string { return "human" }
+
+// ChooseMove asks Input until it gives a legal move. Limits do not apply to
+// people; ctx is checked between attempts.
+func (h *Human) ChooseMove(ctx context.Context, b *checkers.Board, limits SearchLimits) (checkers.Move, error) {
+	legal := b.GenerateAllMoves()
+	if len(legal) == 0 {
+		return checkers.Move{}, ErrGameOver
+	}
+	if h.Input == nil {
+		return checkers.Move{}, errors.New("engine: human player has no input")
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return checkers.Move{}, err
+		}
+		m, err := h.Input(b)
+		if err != nil {
+			return checkers.Move{}, err
+		}
+		for _, l := range legal {
+			if l.Equals(m) {
+				return l, nil
+			}
+		}
+	}
+}
+
+func (h *Human) Info() SearchInfo { return SearchInfo{} }
+
+func (h *Human) Reset() {}
@@ -0,0 +1,169 @@
+// Package match plays engines against each other to measure the difference
+// in strength between them, for instance between two versions of one engine
+// that differ in a single search feature.
+package match
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// Score tallies a match from the first engine's side.
+type Score struct {
+	Wins, Draws, Losses int
+}
+
+// Games returns the number of games played.
+func (s Score) Games() int { return s.Wins + s.Draws + s.Losses }
+
+// Points returns the fraction of the points available that were won.
+func (s Score) Points() float64 {
+	if s.Games() == 0 {
+		return 0.5
+	}
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games())
+}
+
+// Elo returns the rating difference the score suggests and the margin either
+// side of it within which the true difference lies 95% of the time. A match
+// won or lost outright gives an infinite difference, and too few games an
+// infinite margin.
+func (s Score) Elo() (diff, margin float64) {
+	n := float64(s.Games())
+	p := s.Points()
+	diff = elo(p)
+	if n == 0 {
+		return diff, math.Inf(1)
+	}
+	// The standard error of the mean over the results of the single games
+	variance := (float64(s.Wins)*(1-p)*(1-p) + float64(s.Draws)*(0.5-p)*(0.5-p) + float64(s.Losses)*p*p) / n
+	se := math.Sqrt(variance / n)
+	lo, hi := elo(p-1.96*se), elo(p+1.96*se)
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return diff, math.Inf(1)
+	}
+	return diff, (hi - lo) / 2
+}
+
+// elo converts an expected score to a rating difference.
+func elo(p float64) float64 {
+	switch {
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	}
+	return -400 * math.Log10(1/p-1)
+}
+
+func (s Score) String() string {
+	diff, margin := s.Elo()
+	m := "inf"
+	if !math.IsInf(margin, 0) {
+		m = fmt.Sprintf("%.0f", margin)
+	}
+	return fmt.Sprintf("+%d =%d -%d  %.1f%%  Elo %+.0f ± %s", s.Wins, s.Draws, s.Losses, 100*s.Points(), diff, m)
+}
+
+// add counts the result of a game in which a played red if aRed.
+func (s *Score) add(status checkers.Status, aRed bool) {
+	switch {
+	case status == checkers.Draw:
+		s.Draws++
+	case (status == checkers.RedWins) == aRed:
+		s.Wins++
+	default:
+		s.Losses++
+	}
+}
+
+// Game plays red against black from start, which is left as it was, and
+// returns the finished game. Both engines are reset first.
+func Game(ctx context.Context, red, black engine.Engine, start *checkers.Board, limits engine.SearchLimits) (*checkers.Board, error) {
+	red.Reset()
+	black.Reset()
+	b := start.Clone()
+	for !b.Result().IsOver() {
+		p := black
+		if b.BitBoard.IsRedTurn {
+			p = red
+		}
+		m, err := p.ChooseMove(ctx, b, limits)
+		if err != nil {
+			return b, fmt.Errorf("%s: %w", p.Name(), err)
+		}
+		m.MakeMove(b)
+		b.PlyCount++
+	}
+	return b, nil
+}
+
+// Run plays a against b from each opening twice, a taking red in the first
+// game and black in the second, so neither gains from an opening favouring one
+// side. After each game it calls report, if not nil, with the finished game,
+// whether a was red, and the score so far.
+func Run(ctx context.Context, a, b engine.Engine, openings []*checkers.Board, limits engine.SearchLimits,
+	report func(game *checkers.Board, aRed bool, s Score)) (Score, error) {
+	var s Score
+	for _, opening := range openings {
+		for _, aRed := range []bool{true, false} {
+			red, black := a, b
+			if !aRed {
+				red, black = b, a
+			}
+			game, err := Game(ctx, red, black, opening, limits)
+			if err != nil {
+				return s, err
+			}
+			s.add(game.Result().Status, aRed)
+			if report != nil {
+				report(game, aRed, s)
+			}
+		}
+	}
+	return s, nil
+}
+
+// RandomOpenings returns n different positions reached by playing plies
+// random moves from the start, skipping any where the game is already over.
+// The same seed gives the same openings.
+func RandomOpenings(n, plies int, seed int64) []*checkers.Board {
+	r := rand.New(rand.NewSource(seed))
+	seen := make(map[checkers.BitBoard]bool)
+	var openings []*checkers.Board
+	for tries := 0; len(openings) < n && tries < 100*n; tries++ {
+		b := checkers.NewBoard()
+		for i := 0; i < plies && !b.Result().IsOver(); i++ {
+			moves := b.GenerateAllMoves()
+			moves[r.Intn(len(moves))].MakeMove(b)
+			b.PlyCount++
+		}
+		if b.Result().IsOver() || seen[*b.BitBoard] {
+			continue
+		}
+		seen[*b.BitBoard] = true
+		openings = append(openings, b)
+	}
+	return openings
+}
+
+// Ballots returns the position after each ballot of deck, for Run to play
+// from both sides as the three-move restriction has it.
+func Ballots(deck []checkers.Ballot) ([]*checkers.Board, error) {
+	openings := make([]*checkers.Board, 0, len(deck))
+	for _, bl := range deck {
+		b, err := checkers.NewBoardFromBallot(bl)
+		if err != nil {
+			return nil, err
+		}
+		if !b.Result().IsOver() {
+			openings = append(openings, b)
+		}
+	}
+	return openings, nil
+}
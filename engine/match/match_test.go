@@ -0,0 +1,60 @@
+package match
+
+import (
+	"math"
+	"testing"
+
+	"myproject/checkers"
+)
+
+func TestElo(t *testing.T) {
+	tests := []struct {
+		s    Score
+		diff float64
+	}{
+		{Score{Wins: 5, Draws: 10, Losses: 5}, 0},
+		{Score{Wins: 3, Draws: 0, Losses: 1}, 190.8},
+		{Score{Wins: 1, Draws: 0, Losses: 3}, -190.8},
+		{Score{Wins: 0, Draws: 4, Losses: 0}, 0},
+	}
+	for _, tt := range tests {
+		diff, margin := tt.s.Elo()
+		if math.Abs(diff-tt.diff) > 0.1 {
+			t.Errorf("%+v: Elo %.1f, want %.1f", tt.s, diff, tt.diff)
+		}
+		if margin < 0 || math.IsNaN(margin) {
+			t.Errorf("%+v: margin %.1f", tt.s, margin)
+		}
+	}
+	if diff, _ := (Score{Wins: 2}).Elo(); !math.IsInf(diff, 1) {
+		t.Errorf("a clean sweep gives Elo %.1f, want +Inf", diff)
+	}
+}
+
+func TestRandomOpeningsRepeat(t *testing.T) {
+	a, b := RandomOpenings(10, 4, 3), RandomOpenings(10, 4, 3)
+	if len(a) != 10 {
+		t.Fatalf("got %d openings, want 10", len(a))
+	}
+	for i := range a {
+		if *a[i].BitBoard != *b[i].BitBoard {
+			t.Errorf("opening %d differs between runs with the same seed", i)
+		}
+	}
+}
+
+func TestBallots(t *testing.T) {
+	deck := checkers.BalancedDeck()
+	openings, err := Ballots(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(openings) != len(deck) {
+		t.Fatalf("%d openings from %d ballots", len(openings), len(deck))
+	}
+	for i, b := range openings {
+		if n := len(b.Moves()); n != 3 || b.BitBoard.IsRedTurn {
+			t.Fatalf("ballot %s: %d moves played, red to move %v", deck[i], n, b.BitBoard.IsRedTurn)
+		}
+	}
+}
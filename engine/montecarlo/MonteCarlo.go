@@ -0,0 +1,578 @@
+package montecarlo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/book"
+)
+
+type MBot struct {
+	tt      *engine.TransTable
+	rootPly int
+	info    engine.SearchInfo
+	stop    *engine.Stopper
+	pv      engine.PVTable
+
+	probes, hits int
+	selDepth     int
+
+	Book *book.Book // played from without searching, if not nil
+	rng  *rand.Rand // picks among book moves
+
+	engine.Reporter
+}
+
+func NewMBot() *MBot {
+	return &MBot{
+		tt:  engine.NewTransTable(engine.DefaultHashMB),
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// evaluateBoard calculates the board score from the perspective of the red player.
+// Red pieces add points, black pieces subtract points. Kings are worth 1.5 points.
+func (bot *MBot) evaluateBoard(b *checkers.Board) float64 {
+	redScore := 0.0
+	blackScore := 0.0
+
+	redBonus := 0.0
+	blackBonus := 0.0
+
+	pieceCount := 0.0
+
+	blackPieces := []checkers.Spot{}
+	redPieces := []checkers.Spot{}
+
+	furthestRed := checkers.Spot{X: -1, Y: 9}
+	furthestBlack := checkers.Spot{X: -1, Y: -1}
+	for x := 0; x < checkers.BoardSize; x++ {
+		for y := 0; y < checkers.BoardSize; y++ {
+			piece := b.BitBoard.Get(x, y)
+			if piece.Exists == 1 {
+				bonus := 0.0
+				pieceCount++
+				if piece.Red == 1 {
+					redPieces = append(redPieces, checkers.Spot{X: x, Y: y})
+					if furthestRed.Y > y {
+						furthestRed = checkers.Spot{X: x, Y: y}
+					}
+					if y == 7 {
+						redBonus += 0.5
+						if x == 2 || x == 6 {
+							redBonus += 0.4
+						}
+					} else if y == 6 && x == 7 {
+						redBonus += 0.9
+					}
+					if piece.King == 1 {
+						redScore += 2.3
+					} else {
+						redScore += 1
+						bonus += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[7-y]
+						bonus *= []float64{1.08, 1.04, 1.01, 1.06, 1.05, 1.0, 1.03, 1.07}[x]
+					}
+				} else {
+					blackPieces = append(blackPieces, checkers.Spot{X: x, Y: y})
+					if furthestBlack.Y < y {
+						furthestBlack = checkers.Spot{X: x, Y: y}
+					}
+					if y == 0 {
+						blackBonus += 0.5
+						if x == 1 || x == 5 {
+							blackBonus += 0.4
+						}
+					} else if y == 1 && x == 0 {
+						blackBonus += 0.9
+					}
+					if piece.King == 1 {
+						blackScore += 2.3
+					} else {
+						blackScore += 1
+						bonus += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[y]
+						bonus *= []float64{1.07, 1.03, 1.0, 1.05, 1.06, 1.01, 1.04, 1.08}[x]
+					}
+				}
+				if piece.Red == 1 {
+					redScore += bonus
+				} else {
+					blackScore += bonus
+				}
+			}
+		}
+	}
+	for _, bc := range blackPieces {
+		dif := furthestRed.Y - bc.Y
+		if bc.Y < furthestRed.Y && bc.X+dif >= furthestRed.X && furthestRed.X+dif <= bc.X { // the cone of blocking
+			redScore += 0.01 / float64(dif)
+		}
+	}
+	for _, rc := range redPieces {
+		dif := rc.Y - furthestBlack.Y
+		if rc.Y > furthestBlack.Y && rc.X+dif >= furthestBlack.X && furthestBlack.X+dif <= rc.X { // the cone of blocking
+			blackScore += 0.01 / float64(dif)
+		}
+	}
+	redScore += redBonus
+	blackScore += blackBonus
+	return redScore - blackScore
+}
+
+func (bot *MBot) getPosition(b *checkers.Board) (engine.Entry, bool) {
+	bot.probes++
+	e, ok := bot.tt.Probe(b.BitBoard)
+	if ok {
+		bot.hits++
+		e.Pos.Value = engine.ScoreFromTT(e.Pos.Value, b.PlyCount-bot.rootPly)
+	}
+	return e, ok
+}
+
+func (bot *MBot) storePosition(b *checkers.Board, e engine.Entry) {
+	e.Pos.Value = engine.ScoreToTT(e.Pos.Value, b.PlyCount-bot.rootPly)
+	bot.tt.Store(b.BitBoard, e)
+}
+
+func (bot *MBot) basicSort(b *checkers.Board) []checkers.Move {
+	var positions []engine.Position
+	scalar := 1.0
+	if !b.BitBoard.IsRedTurn {
+		scalar = -1.0
+	}
+	thisPos, okf := bot.getPosition(b)
+	for _, move := range b.GenerateAllMoves() {
+		var value float64 = 0
+		if okf && !thisPos.Pos.Move.IsNull() && thisPos.Pos.Move.Equals(move) {
+			value = 10_000
+			move.IsSuperior = true
+		}
+		b.Save()
+		move.MakeMove(b)
+		entry, ok := bot.getPosition(b)
+		if move.Jumps > 1 {
+			value += 100
+		}
+		b.Load()
+		if ok {
+			value += entry.Pos.Value * scalar
+			// Add the calculated position to the slice
+			positions = append(positions, engine.Position{
+				Value: value * 1_000,
+				Move:  move,
+			})
+			continue
+		}
+		// Apply sorting logic for 'isMax'
+		if b.BitBoard.IsRedTurn {
+			if move.MovedPiece.King != 0 { // If not a king
+				value += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[7-move.ToY]
+				value *= []float64{1.07, 1.03, 1.0, 1.05, 1.06, 1.01, 1.04, 1.08}[move.ToX]
+				if move.FromY == 7 {
+					value -= 1000
+				}
+				if move.ToY == 0 {
+					value += 1000
+				}
+			}
+		} else {
+			// Apply sorting logic for 'isMin'
+			if move.MovedPiece.King != 0 { // If not a king
+				value += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[move.ToY]
+				value *= []float64{1.07, 1.03, 1.0, 1.05, 1.06, 1.01, 1.04, 1.08}[move.ToX]
+				if move.FromY == 0 {
+					value -= 1000
+				}
+				if move.ToY == 7 {
+					value += 1000
+				}
+			}
+		}
+
+		// Add the calculated position to the slice
+		positions = append(positions, engine.Position{
+			Value: value,
+			Move:  move,
+		})
+	}
+
+	// Sort positions based on the value
+	sort.Sort(engine.ByValue(positions))
+
+	// Extract the sorted moves from positions
+	var sortedMoves []checkers.Move
+	for _, pos := range positions {
+		sortedMoves = append(sortedMoves, pos.Move)
+	}
+
+	return sortedMoves
+}
+
+func (bot *MBot) qsearch(b *checkers.Board, alpha float64, beta float64, depth float64) float64 {
+	b.NodeBudget--
+	if bot.stop.Stop(b) {
+		return 0
+	}
+	bot.selDepth = max(bot.selDepth, b.PlyCount-bot.rootPly)
+	standPat := bot.evaluateBoard(b)
+	if !b.BitBoard.IsRedTurn {
+		standPat *= -1
+	}
+	if standPat >= beta {
+		return beta
+	}
+	if alpha < standPat {
+		alpha = standPat
+	}
+
+	allMoves := b.GenerateAllMoves()
+	if len(allMoves) == 0 {
+		return engine.LossScore(b.PlyCount - bot.rootPly)
+	}
+
+	for _, move := range allMoves {
+		checkMove := false
+		if move.IsJump {
+			checkMove = true
+		} else if move.ToY == 0 && move.MovedPiece.Red == 1 {
+			checkMove = true
+		} else if move.ToY == 7 && move.MovedPiece.Red == 0 {
+			checkMove = true
+		}
+		if !checkMove {
+			break
+		}
+		b.Save()
+		move.MakeMove(b)
+		score := -bot.qsearch(b, -beta, -alpha, depth+1)
+		b.Load()
+
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+	return alpha
+}
+func (bot *MBot) Bnegascout(b *checkers.Board, depth float64, alpha float64, beta float64) engine.Position {
+	b.NodeBudget--
+	// The scores of an abandoned search are thrown away, so give up at once
+	if bot.stop.Stop(b) {
+		return engine.Position{}
+	}
+
+	entry, ok := bot.getPosition(b)
+	if ok && entry.Depth >= depth { // Only use entries with at least the current depth
+		if entry.Typ == engine.EXACT {
+			return entry.Pos
+		}
+		if entry.Typ == engine.LOWERBOUND {
+			alpha = math.Max(alpha, entry.Pos.Value)
+		}
+		if entry.Typ == engine.UPPERBOUND {
+			beta = math.Min(beta, entry.Pos.Value)
+		}
+		if alpha >= beta {
+			return entry.Pos // Prune
+		}
+	}
+
+	// Base case: if depth is 0 or no moves are available, return the board evaluation
+	if depth <= 0 {
+		return engine.Position{Value: bot.qsearch(b, alpha, beta, depth)}
+	}
+
+	var allMoves []checkers.Move
+	if depth >= 4 {
+		allMoves = bot.basicSort(b)
+	} else {
+		allMoves = b.GenerateAllMoves()
+	}
+
+	if len(allMoves) == 0 {
+		return engine.Position{Value: engine.LossScore(b.PlyCount - bot.rootPly)}
+	}
+
+	bestMove := engine.Position{Value: -math.MaxFloat64} // Start with very low value (negascout maximizes this)
+	for _, move := range allMoves {
+		inc := 1.0
+		if move.IsSuperior {
+			inc = 0.9
+		}
+		b.Save()
+		move.MakeMove(b)
+		final := bot.Bnegascout(b, depth-inc, -beta, -alpha)
+		final.Value *= -1
+		b.Load()
+		if bot.stop.Stopped() {
+			return bestMove
+		}
+
+		if final.Value > bestMove.Value {
+			bestMove.Value = final.Value
+			bestMove.Move = move
+		}
+		alpha = math.Max(alpha, bestMove.Value)
+
+		if alpha >= beta {
+			break
+		}
+	}
+	return bestMove
+}
+func (bot *MBot) negascout(b *checkers.Board, depth float64, alpha float64, beta float64) engine.Position {
+	b.NodeBudget--
+	ply := b.PlyCount - bot.rootPly
+	bot.pv.Clear(ply)
+	bot.selDepth = max(bot.selDepth, ply)
+	// The scores of an abandoned search are thrown away, so give up at once
+	if bot.stop.Stop(b) {
+		return engine.Position{}
+	}
+	ao := alpha
+
+	// A repeated position inside the tree is scored as a draw, so a winning side
+	// avoids cycling and a losing side steers into one
+	if b.PlyCount > bot.rootPly {
+		if draw, _ := b.IsDraw(); draw || b.Repetitions() > 1 {
+			return engine.Position{Value: 0}
+		}
+	}
+
+	entry, ok := bot.getPosition(b)
+	if ok && entry.Depth >= depth { // Only use entries with at least the current depth
+		if entry.Typ == engine.EXACT {
+			return entry.Pos
+		}
+		if entry.Typ == engine.LOWERBOUND {
+			alpha = math.Max(alpha, entry.Pos.Value)
+		}
+		if entry.Typ == engine.UPPERBOUND {
+			beta = math.Min(beta, entry.Pos.Value)
+		}
+		if alpha >= beta {
+			return entry.Pos // Prune
+		}
+	}
+
+	// Base case: if depth is 0 or no moves are available, return the board evaluation
+	if depth <= 0 {
+		return engine.Position{Value: bot.monteHybridEval(b)}
+	}
+
+	var allMoves []checkers.Move
+	if depth >= 4 {
+		allMoves = bot.basicSort(b)
+	} else {
+		allMoves = b.GenerateAllMoves()
+	}
+
+	if len(allMoves) == 0 {
+		return engine.Position{Value: engine.LossScore(b.PlyCount - bot.rootPly)}
+	}
+
+	bestMove := engine.Position{Value: -math.MaxFloat64} // Start with very low value (negascout maximizes this)
+	for _, move := range allMoves {
+		inc := 1.0
+		if move.IsSuperior {
+			inc = 0.9
+		}
+		b.Save()
+		move.MakeMove(b)
+		final := bot.negascout(b, depth-inc, -beta, -alpha)
+		final.Value *= -1
+		b.Load()
+		if bot.stop.Stopped() {
+			return bestMove
+		}
+
+		if final.Value > bestMove.Value {
+			bestMove.Value = final.Value
+			bestMove.Move = move
+			bot.pv.Update(ply, move)
+		}
+		alpha = math.Max(alpha, bestMove.Value)
+
+		if alpha >= beta {
+			break
+		}
+	}
+	tte := engine.Entry{Pos: bestMove, Depth: depth, Typ: 0}
+	if bestMove.Value <= ao {
+		tte.Typ = engine.UPPERBOUND
+	} else if bestMove.Value >= beta {
+		tte.Typ = engine.LOWERBOUND
+	} else {
+		tte.Typ = engine.EXACT
+	}
+	bot.storePosition(b, tte)
+	return bestMove
+}
+
+// progress returns the search's state so far.
+func (bot *MBot) progress(b *checkers.Board, startBudget int, startTime time.Time) engine.SearchInfo {
+	info := bot.info
+	info.Time = time.Since(startTime)
+	info.Nodes = startBudget - b.NodeBudget
+	info.Hashfull = bot.tt.Stats().Hashfull
+	return info.WithRates()
+}
+
+// recursiveDeepening implements the recursive deepening search strategy
+func (bot *MBot) recursiveDeepening(ctx context.Context, b *checkers.Board, limits engine.SearchLimits) engine.Position {
+	startTime := time.Now()
+	tm := engine.NewTimeManager(limits, startTime)
+	bot.stop = engine.NewStopper(ctx, tm.Deadline())
+	bot.rootPly = b.PlyCount
+	bot.info = engine.SearchInfo{}
+	bot.selDepth = 0
+	startBudget := b.NodeBudget
+	var lmm engine.Position
+	bot.stop.OnPoll(func(b *checkers.Board) { bot.Progress(startBudget - b.NodeBudget) })
+	depth := 2
+	maxDepth := limits.MaxDepth()
+	if maxDepth > 0 {
+		depth = min(depth, maxDepth)
+	}
+
+	for {
+		// Perform the Minimax search with the current depth
+		mm := bot.negascout(b, float64(depth), -1_000_000_000, 1_000_000_000)
+
+		// An unfinished iteration is only used if no iteration finished at all
+		if bot.stop.Stopped() {
+			if lmm.Move.IsNull() {
+				lmm = mm
+			}
+			break
+		}
+
+		// Spend longer on the move while the search keeps changing its mind
+		if !lmm.Move.IsNull() && !lmm.Move.Equals(mm.Move) {
+			tm.BestMoveChanged()
+		}
+
+		// Update the best move found at this depth
+		lmm = mm
+		bot.info.Depth = depth
+		bot.info.Score = mm.Value
+		bot.info.PV = bot.tt.ExtendPV(b, bot.pv.Line(0))
+		bot.info.Lines = []engine.Line{{Score: mm.Value, PV: bot.info.PV}}
+		bot.info.SelDepth = bot.selDepth
+		bot.Publish(bot.progress(b, startBudget, startTime))
+
+		if engine.IsDecided(mm.Value) || depth == maxDepth || tm.PastSoft() {
+			// An infinite search only answers when told to stop
+			if limits.Infinite {
+				<-ctx.Done()
+			}
+			break
+		}
+
+		// Increase the search depth for the next iteration
+		depth++
+	}
+	bot.info.Time = time.Since(startTime)
+
+	// Return the best move found within the limits
+	return lmm
+}
+
+func (bot *MBot) monteHybridEval(b *checkers.Board) float64 {
+	const moves = 7
+	const depth = 5
+	nj := 0.0
+	lastEval := 0.0
+	out := 0.0
+	b.Save()
+	for i := 0.0; i < moves; i++ {
+		best := bot.Bnegascout(b, depth-out/2, -100_000_000_000, 100_000_000_000)
+		if best.Move.IsNull() {
+			break
+		}
+		if best.Move.IsJump && i == moves-1 {
+			i--
+		} else {
+			lastEval += best.Value
+			nj++
+		}
+		if math.Abs(lastEval) > 1_000 {
+			break
+		}
+		best.Move.MakeMove(b)
+		out++
+	}
+	lastEval /= nj
+	b.Load()
+	return lastEval
+}
+
+func (bot *MBot) Name() string { return "montecarlo" }
+
+// ChooseMove searches b until a limit is reached or ctx is cancelled and
+// returns the best move found.
+func (bot *MBot) ChooseMove(ctx context.Context, b *checkers.Board, limits engine.SearchLimits) (checkers.Move, error) {
+	// Nothing to search once the game is decided
+	if b.Result().IsOver() {
+		return checkers.Move{}, engine.ErrGameOver
+	}
+	limits = limits.WithDefaults()
+	bot.Start()
+	// A forced move needs no thought, unless we were asked to analyse
+	if moves := b.GenerateAllMoves(); len(moves) == 1 && !limits.Infinite {
+		bot.info = engine.SearchInfo{}
+		bot.Finish(bot.info)
+		return moves[0], nil
+	}
+	// Known openings need no thought either
+	if m, ok := bot.Book.Probe(b, bot.rng); ok && !limits.Infinite {
+		bot.info = engine.SearchInfo{}
+		bot.Finish(bot.info)
+		bot.Logf("bestmove %s from the book", m.String())
+		return m, nil
+	}
+	bot.tt.NewSearch()
+	bot.probes, bot.hits = 0, 0
+	budget := limits.NodeBudget()
+	b.NodeBudget = budget
+
+	// Use recursive deepening to find the best move within the limits
+	mm := bot.recursiveDeepening(ctx, b, limits)
+	bot.info.Nodes = budget - b.NodeBudget
+	// Stopped before a single move was searched; any legal move beats none
+	if mm.Move.IsNull() {
+		mm.Move = b.GenerateAllMoves()[0]
+	}
+
+	bot.info.Hashfull = bot.tt.Stats().Hashfull
+	bot.info = bot.info.WithRates()
+	bot.Finish(bot.info)
+	bot.Logf("bestmove %s score %s depth %d nodes %d time %s hash hits %d of %d",
+		mm.Move.String(), engine.FormatScore(mm.Value), bot.info.Depth, bot.info.Nodes, bot.info.Time.Round(time.Millisecond), bot.hits, bot.probes)
+	return mm.Move, nil
+}
+
+func (bot *MBot) Info() engine.SearchInfo { return bot.info }
+
+// Reset clears the transposition table for a new game.
+func (bot *MBot) Reset() { bot.tt.Clear() }
+
+// SetOption sets "book" to the name of an opening book file to play from, or
+// "" for none.
+func (bot *MBot) SetOption(name, value string) (err error) {
+	if name != "book" {
+		return fmt.Errorf("montecarlo: %w %q", engine.ErrUnknownOption, name)
+	}
+	bot.Book = nil
+	if value != "" {
+		bot.Book, err = book.Open(value)
+	}
+	return err
+}
@@ -0,0 +1,717 @@
+package negascout
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/book"
+	"myproject/engine/endgame"
+)
+
+// infinity bounds the full search window; no score reaches it.
+const infinity = 1_000_000_000
+
+// Aspiration windows: the first search of an iteration looks this far either
+// side of the last iteration's score, and gives up on windows once they have
+// grown past the maximum.
+const (
+	aspirationWindow    = 0.25
+	maxAspirationWindow = 16
+)
+
+// Late move reductions: quiet moves from the lmrMoves-th on, at nodes with at
+// least lmrDepth to go, are searched a ply less deeply, and two plies once
+// both are doubled.
+const (
+	lmrMoves = 3
+	lmrDepth = 3
+)
+
+// Features switches parts of the search on and off, so that bots differing
+// in one can be played against each other to measure what it is worth.
+type Features struct {
+	PVS        bool // null-window searches of all but the first move
+	LMR        bool // late move reductions
+	Aspiration bool // aspiration windows at the root
+}
+
+// AllFeatures is what NewBot searches with.
+var AllFeatures = Features{PVS: true, LMR: true, Aspiration: true}
+
+type Bot struct {
+	tt       *engine.TransTable
+	rootPly  int
+	info     engine.SearchInfo
+	moveBufs [][]checkers.Move // one reusable move list per ply from the root
+	stop     *engine.Stopper
+	pv       engine.PVTable
+	excluded []checkers.Move // root moves already ranked in a MultiPV search
+	order    heuristics
+	pickers  []*movePicker // one per ply from the root
+
+	// Threads is how many searches run at once, sharing the transposition
+	// table. With one thread the search is deterministic.
+	Threads     int
+	Features    Features
+	Endgame     *endgame.DB        // consulted for positions with few pieces, if not nil
+	Distances   *endgame.Distances // likewise, and preferred for its exact scores
+	Book        *book.Book         // played from without searching, if not nil
+	rng         *rand.Rand         // picks among book moves
+	helpers     []*Bot             // the extra threads, kept so their buffers are reused
+	depthOffset int                // how much deeper than the main thread a helper starts
+	quiet       bool               // a helper or ponder search, which prints nothing
+
+	mu        sync.Mutex
+	pondering *ponder // the search running on the opponent's time, if any
+
+	probes, hits int
+	egHits       int // positions scored from the endgame database
+	selDepth     int
+
+	engine.Reporter
+}
+
+func NewBot() *Bot {
+	return &Bot{
+		tt:       engine.NewTransTable(engine.DefaultHashMB),
+		Threads:  1,
+		Features: AllFeatures,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// evaluateBoard calculates the board score from the perspective of the red player.
+// Red pieces add points, black pieces subtract points. Kings are worth 1.5 points.
+func (bot *Bot) evaluateBoard(b *checkers.Board) float64 {
+	redScore := 0.0
+	blackScore := 0.0
+
+	redBonus := 0.0
+	blackBonus := 0.0
+
+	pieceCount := 0.0
+
+	blackPieces := []checkers.Spot{}
+	redPieces := []checkers.Spot{}
+
+	furthestRed := checkers.Spot{X: -1, Y: 9}
+	furthestBlack := checkers.Spot{X: -1, Y: -1}
+	for x := 0; x < checkers.BoardSize; x++ {
+		for y := 0; y < checkers.BoardSize; y++ {
+			piece := b.BitBoard.Get(x, y)
+			if piece.Exists == 1 {
+				bonus := 0.0
+				pieceCount++
+				if piece.Red == 1 {
+					redPieces = append(redPieces, checkers.Spot{X: x, Y: y})
+					if furthestRed.Y > y {
+						furthestRed = checkers.Spot{X: x, Y: y}
+					}
+					if y == 7 {
+						redBonus += 0.5
+						if x == 2 || x == 6 {
+							redBonus += 0.4
+						}
+					} else if y == 6 && x == 7 {
+						redBonus += 0.9
+					}
+					if piece.King == 1 {
+						redScore += 2.3
+					} else {
+						redScore += 1
+						bonus += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[7-y]
+						bonus *= []float64{1.08, 1.04, 1.01, 1.06, 1.05, 1.0, 1.03, 1.07}[x]
+					}
+				} else {
+					blackPieces = append(blackPieces, checkers.Spot{X: x, Y: y})
+					if furthestBlack.Y < y {
+						furthestBlack = checkers.Spot{X: x, Y: y}
+					}
+					if y == 0 {
+						blackBonus += 0.5
+						if x == 1 || x == 5 {
+							blackBonus += 0.4
+						}
+					} else if y == 1 && x == 0 {
+						blackBonus += 0.9
+					}
+					if piece.King == 1 {
+						blackScore += 2.3
+					} else {
+						blackScore += 1
+						bonus += []float64{0.2, 0.0, 0.06, 0.08, 0.1, 0.2, 0.4, 0.0}[y]
+						bonus *= []float64{1.07, 1.03, 1.0, 1.05, 1.06, 1.01, 1.04, 1.08}[x]
+					}
+				}
+				if piece.Red == 1 {
+					redScore += bonus
+				} else {
+					blackScore += bonus
+				}
+			}
+		}
+	}
+	for _, bc := range blackPieces {
+		dif := furthestRed.Y - bc.Y
+		if bc.Y < furthestRed.Y && bc.X+dif >= furthestRed.X && furthestRed.X+dif <= bc.X { // the cone of blocking
+			redScore += 0.01 / float64(dif)
+		}
+	}
+	for _, rc := range redPieces {
+		dif := rc.Y - furthestBlack.Y
+		if rc.Y > furthestBlack.Y && rc.X+dif >= furthestBlack.X && furthestBlack.X+dif <= rc.X { // the cone of blocking
+			blackScore += 0.01 / float64(dif)
+		}
+	}
+	redScore += redBonus
+	blackScore += blackBonus
+	return redScore - blackScore
+}
+
+// movesAt generates the moves at the current node into the buffer for its ply,
+// so the search does not allocate a new slice at every node.
+func (bot *Bot) movesAt(b *checkers.Board) []checkers.Move {
+	ply := b.PlyCount - bot.rootPly
+	if ply < 0 {
+		return b.GenerateAllMoves()
+	}
+	for len(bot.moveBufs) <= ply {
+		bot.moveBufs = append(bot.moveBufs, make([]checkers.Move, 0, 32))
+	}
+	bot.moveBufs[ply] = b.AppendMoves(bot.moveBufs[ply][:0])
+	return bot.moveBufs[ply]
+}
+
+func (bot *Bot) getPosition(b *checkers.Board) (engine.Entry, bool) {
+	bot.probes++
+	e, ok := bot.tt.Probe(b.BitBoard)
+	if ok {
+		bot.hits++
+		e.Pos.Value = engine.ScoreFromTT(e.Pos.Value, b.PlyCount-bot.rootPly)
+	}
+	return e, ok
+}
+
+func (bot *Bot) storePosition(b *checkers.Board, e engine.Entry) {
+	e.Pos.Value = engine.ScoreToTT(e.Pos.Value, b.PlyCount-bot.rootPly)
+	bot.tt.Store(b.BitBoard, e)
+}
+
+func (bot *Bot) qsearch(b *checkers.Board, alpha float64, beta float64, depth float64) float64 {
+	b.NodeBudget--
+	if bot.stop.Stop(b) {
+		return 0
+	}
+	bot.selDepth = max(bot.selDepth, b.PlyCount-bot.rootPly)
+	if score, ok := bot.probeEndgame(b); ok {
+		return score
+	}
+	standPat := bot.evaluateBoard(b)
+	if !b.BitBoard.IsRedTurn {
+		standPat *= -1
+	}
+	if standPat >= beta {
+		return beta
+	}
+	if alpha < standPat {
+		alpha = standPat
+	}
+
+	allMoves := bot.movesAt(b)
+	if len(allMoves) == 0 {
+		return engine.LossScore(b.PlyCount - bot.rootPly)
+	}
+
+	for _, move := range allMoves {
+		checkMove := false
+		if move.IsJump {
+			checkMove = true
+		} else if move.ToY == 0 && move.MovedPiece.Red == 1 {
+			checkMove = true
+		} else if move.ToY == 7 && move.MovedPiece.Red == 0 {
+			checkMove = true
+		}
+		if !checkMove {
+			break
+		}
+		b.Save()
+		move.MakeMove(b)
+		score := -bot.qsearch(b, -beta, -alpha, depth+1)
+		b.Load()
+
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+	return alpha
+}
+
+// probeEndgame scores b from the endgame tables, if it has few enough pieces.
+// The distance tables give the exact score of a win or a loss, so the search
+// takes the quickest win and the longest defence. With only the database a
+// won or lost position keeps its evaluation on top of KnownWin, so the
+// search still heads for the simplest win, though not always the quickest. A
+// drawn one scores nothing.
+func (bot *Bot) probeEndgame(b *checkers.Board) (float64, bool) {
+	if b.PlyCount == bot.rootPly {
+		return 0, false
+	}
+	ply := b.PlyCount - bot.rootPly
+	if v, d, ok := bot.Distances.Probe(b.BitBoard); ok {
+		bot.egHits++
+		switch v {
+		case endgame.Win:
+			return -engine.LossScore(ply + d), true
+		case endgame.Loss:
+			return engine.LossScore(ply + d), true
+		}
+		return 0, true
+	}
+	v, ok := bot.Endgame.Probe(b.BitBoard)
+	if !ok {
+		return 0, false
+	}
+	bot.egHits++
+	eval := bot.evaluateBoard(b)
+	if !b.BitBoard.IsRedTurn {
+		eval *= -1
+	}
+	switch v {
+	case endgame.Win:
+		return engine.KnownWin + eval, true
+	case endgame.Loss:
+		return -engine.KnownWin + eval, true
+	}
+	return 0, true
+}
+
+func (bot *Bot) negascout(b *checkers.Board, depth float64, alpha float64, beta float64) engine.Position {
+	b.NodeBudget--
+	ply := b.PlyCount - bot.rootPly
+	bot.pv.Clear(ply)
+	bot.selDepth = max(bot.selDepth, ply)
+	// The scores of an abandoned search are thrown away, so give up at once
+	if bot.stop.Stop(b) {
+		return engine.Position{}
+	}
+	ao := alpha
+
+	// A repeated position inside the tree is scored as a draw, so a winning side
+	// avoids cycling and a losing side steers into one
+	if b.PlyCount > bot.rootPly {
+		if draw, _ := b.IsDraw(); draw || b.Repetitions() > 1 {
+			return engine.Position{Value: 0}
+		}
+		if score, ok := bot.probeEndgame(b); ok {
+			return engine.Position{Value: score}
+		}
+	}
+
+	// While ranking root moves the table's answer for the root may be one
+	// already ranked, so it is neither used nor replaced
+	ranking := ply == 0 && len(bot.excluded) > 0
+
+	entry, ok := bot.getPosition(b)
+	if ok && entry.Depth >= depth && !ranking { // Only use entries with at least the current depth
+		if entry.Typ == engine.EXACT {
+			return entry.Pos
+		}
+		if entry.Typ == engine.LOWERBOUND {
+			alpha = math.Max(alpha, entry.Pos.Value)
+		}
+		if entry.Typ == engine.UPPERBOUND {
+			beta = math.Min(beta, entry.Pos.Value)
+		}
+		if alpha >= beta {
+			return entry.Pos // Prune
+		}
+	}
+
+	// Base case: if depth is 0 or no moves are available, return the board evaluation
+	if depth <= 0 {
+		return engine.Position{Value: bot.qsearch(b, alpha, beta, depth)}
+	}
+
+	mp := bot.newPicker(b, ply, entry.Pos.Move)
+	if len(mp.moves) == 0 {
+		return engine.Position{Value: engine.LossScore(b.PlyCount - bot.rootPly)}
+	}
+
+	// child searches the move just made from the mover's point of view
+	child := func(depth, alpha, beta float64) engine.Position {
+		p := bot.negascout(b, depth, -beta, -alpha)
+		p.Value *= -1
+		return p
+	}
+
+	bestMove := engine.Position{Value: -math.MaxFloat64} // Start with very low value (negascout maximizes this)
+	searched := 0
+	for move, more := mp.Next(b, &bot.order, ply); more; move, more = mp.Next(b, &bot.order, ply) {
+		if ranking && bot.isExcluded(move) {
+			continue
+		}
+		inc := 1.0
+		if move.IsSuperior {
+			inc = 0.9
+		}
+		// The first move is searched with the full window. The rest only
+		// have to be shown no better than it, which a null window does
+		// more cheaply, and quiet moves ordered late are searched less
+		// deeply too. Any that beat alpha after all are searched again.
+		scoutBeta := beta
+		if bot.Features.PVS && searched > 0 {
+			scoutBeta = math.Nextafter(alpha, math.Inf(1))
+		}
+		r := bot.reduction(depth, searched, mp)
+		b.Save()
+		move.MakeMove(b)
+		final := child(depth-inc-r, alpha, scoutBeta)
+		if final.Value > alpha && r > 0 {
+			final = child(depth-inc, alpha, scoutBeta)
+		}
+		if final.Value > alpha && final.Value < beta && scoutBeta < beta {
+			final = child(depth-inc, alpha, beta)
+		}
+		b.Load()
+		searched++
+		if bot.stop.Stopped() {
+			return bestMove
+		}
+
+		if final.Value > bestMove.Value {
+			bestMove.Value = final.Value
+			bestMove.Move = move
+			bot.pv.Update(ply, move)
+		}
+		alpha = math.Max(alpha, bestMove.Value)
+
+		if alpha >= beta {
+			bot.order.cutoff(b, ply, &move, depth)
+			break
+		}
+	}
+	tte := engine.Entry{Pos: bestMove, Depth: depth, Typ: 0}
+	if bestMove.Value <= ao {
+		tte.Typ = engine.UPPERBOUND
+	} else if bestMove.Value >= beta {
+		tte.Typ = engine.LOWERBOUND
+	} else {
+		tte.Typ = engine.EXACT
+	}
+	if !ranking {
+		bot.storePosition(b, tte)
+	}
+	return bestMove
+}
+
+// progress returns the search's state so far.
+func (bot *Bot) progress(b *checkers.Board, startBudget int, startTime time.Time) engine.SearchInfo {
+	info := bot.info
+	info.Time = time.Since(startTime)
+	info.Nodes = startBudget - b.NodeBudget
+	info.Hashfull = bot.tt.Stats().Hashfull
+	return info.WithRates()
+}
+
+// recursiveDeepening implements the recursive deepening search strategy
+func (bot *Bot) recursiveDeepening(ctx context.Context, b *checkers.Board, limits engine.SearchLimits) engine.Position {
+	startTime := time.Now()
+	tm := engine.NewTimeManager(limits, startTime)
+	bot.stop = engine.NewStopper(ctx, tm.Deadline())
+	bot.rootPly = b.PlyCount
+	bot.info = engine.SearchInfo{}
+	bot.selDepth = 0
+	startBudget := b.NodeBudget
+	var lmm engine.Position
+	if !bot.quiet {
+		bot.stop.OnPoll(func(b *checkers.Board) { bot.Progress(startBudget - b.NodeBudget) })
+	}
+	depth := 9 + bot.depthOffset
+	maxDepth := limits.MaxDepth()
+	if maxDepth > 0 {
+		depth = min(depth, maxDepth)
+	}
+
+	lines := min(max(limits.MultiPV, 1), len(b.GenerateAllMoves()))
+	// Every move from a position the endgame database holds leads straight
+	// to another it holds, so searching deeper finds nothing new
+	_, known := bot.Endgame.Probe(b.BitBoard)
+	if _, _, ok := bot.Distances.Probe(b.BitBoard); ok {
+		known = true
+	}
+
+	for {
+		// Perform the Minimax search with the current depth, once for each
+		// line asked for, leaving out the root moves already ranked
+		mm, ranked := bot.rankRootMoves(b, float64(depth), lines, lmm)
+
+		// An unfinished iteration, including one stopped partway through
+		// ranking the lines, is only used if no iteration finished at all
+		if bot.stop.Stopped() {
+			if lmm.Move.IsNull() {
+				lmm = mm
+			}
+			break
+		}
+
+		// Spend longer on the move while the search keeps changing its mind
+		if !lmm.Move.IsNull() && !lmm.Move.Equals(mm.Move) {
+			tm.BestMoveChanged()
+		}
+
+		// Update the best move found at this depth
+		lmm = mm
+		bot.info.Depth = depth
+		bot.info.Score = mm.Value
+		bot.info.PV = ranked[0].PV
+		bot.info.Lines = ranked
+		bot.info.SelDepth = bot.selDepth
+		if !bot.quiet {
+			bot.Publish(bot.progress(b, startBudget, startTime))
+		}
+
+		if engine.IsDecided(mm.Value) || known || depth == maxDepth || tm.PastSoft() {
+			// An infinite search only answers when told to stop
+			if limits.Infinite {
+				<-ctx.Done()
+			}
+			break
+		}
+
+		// Increase the search depth for the next iteration
+		depth++
+	}
+	bot.info.Time = time.Since(startTime)
+
+	// Return the best move found within the limits
+	return lmm
+}
+
+// rankRootMoves searches b to depth for its best n root moves in turn, each
+// search leaving out the moves ranked before it. It returns the best result
+// and the lines ranked. If the search is stopped the best result may be from
+// an unfinished search. prev is the last iteration's best result, if any,
+// which the search for the best move expects to come out close to.
+//
+// The searches after the first are capped at the score of the line before:
+// the table can make a later search see a little further than an earlier
+// one, and a move found no worse than the one ranked above it ties with it.
+func (bot *Bot) rankRootMoves(b *checkers.Board, depth float64, n int, prev engine.Position) (engine.Position, []engine.Line) {
+	var best engine.Position
+	var ranked []engine.Line
+	bot.excluded = bot.excluded[:0]
+	defer func() { bot.excluded = bot.excluded[:0] }()
+	for len(ranked) < n {
+		var mm engine.Position
+		if ranked == nil && bot.Features.Aspiration && !prev.Move.IsNull() && !engine.IsDecided(prev.Value) {
+			mm = bot.aspirate(b, depth, prev.Value)
+		} else if ranked == nil {
+			mm = bot.negascout(b, depth, -infinity, infinity)
+		} else {
+			cap := ranked[len(ranked)-1].Score
+			mm = bot.negascout(b, depth, -infinity, cap)
+			mm.Value = math.Min(mm.Value, cap)
+		}
+		if bot.stop.Stopped() {
+			if ranked == nil {
+				best = mm
+			}
+			break
+		}
+		if ranked == nil {
+			best = mm
+		}
+		ranked = append(ranked, engine.Line{Score: mm.Value, PV: bot.tt.ExtendPV(b, bot.pv.Line(0))})
+		bot.excluded = append(bot.excluded, mm.Move)
+	}
+	return best, ranked
+}
+
+// aspirate searches the root to depth with a narrow window around guess,
+// widening it on whichever side the score falls outside until it falls inside.
+func (bot *Bot) aspirate(b *checkers.Board, depth float64, guess float64) engine.Position {
+	delta := aspirationWindow
+	alpha, beta := guess-delta, guess+delta
+	for {
+		mm := bot.negascout(b, depth, alpha, beta)
+		if bot.stop.Stopped() || (mm.Value > alpha && mm.Value < beta) {
+			return mm
+		}
+		delta *= 4
+		if mm.Value <= alpha {
+			alpha = guess - delta
+		} else {
+			beta = guess + delta
+		}
+		if delta > maxAspirationWindow {
+			alpha, beta = -infinity, infinity
+		}
+	}
+}
+
+// reduction returns how much less deeply to search the move mp handed out
+// last, the searched-th at a node with depth to go.
+func (bot *Bot) reduction(depth float64, searched int, mp *movePicker) float64 {
+	if !bot.Features.LMR || searched < lmrMoves || depth < lmrDepth || !mp.quiet() {
+		return 0
+	}
+	if searched >= 2*lmrMoves && depth >= 2*lmrDepth {
+		return 2
+	}
+	return 1
+}
+
+// isExcluded reports whether a MultiPV search has already ranked m.
+func (bot *Bot) isExcluded(m checkers.Move) bool {
+	for i := range bot.excluded {
+		if bot.excluded[i].Equals(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// search runs one search of b, with any helper threads filling the table
+// until it is done.
+func (bot *Bot) search(ctx context.Context, b *checkers.Board, limits engine.SearchLimits) engine.Position {
+	bot.tt.NewSearch()
+	bot.order.clear()
+	bot.probes, bot.hits, bot.egHits = 0, 0, 0
+	budget := limits.NodeBudget()
+	b.NodeBudget = budget
+
+	// Use recursive deepening to find the best move within the limits
+	wait := bot.startHelpers(ctx, b)
+	mm := bot.recursiveDeepening(ctx, b, limits)
+	bot.info.Nodes = budget - b.NodeBudget + wait()
+	return mm
+}
+
+func (bot *Bot) Name() string { return "negascout" }
+
+// ChooseMove searches b until a limit is reached or ctx is cancelled and
+// returns the best move found.
+func (bot *Bot) ChooseMove(ctx context.Context, b *checkers.Board, limits engine.SearchLimits) (checkers.Move, error) {
+	// Nothing to search once the game is decided
+	if b.Result().IsOver() {
+		return checkers.Move{}, engine.ErrGameOver
+	}
+	limits = limits.WithDefaults()
+	bot.Start()
+	p := bot.takePonder()
+	// A forced move needs no thought, unless we were asked to analyse
+	if moves := b.GenerateAllMoves(); len(moves) == 1 && !limits.Infinite {
+		p.stop()
+		bot.info = engine.SearchInfo{}
+		bot.Finish(bot.info)
+		return moves[0], nil
+	}
+	// Known openings need no thought either
+	if m, ok := bot.Book.Probe(b, bot.rng); ok && !limits.Infinite {
+		p.stop()
+		bot.info = engine.SearchInfo{}
+		bot.Finish(bot.info)
+		bot.Logf("bestmove %s from the book", m.String())
+		return m, nil
+	}
+
+	// If the opponent played the reply we were pondering on, that search is
+	// already well under way; otherwise it at least left the table warm
+	var mm engine.Position
+	if p.hit(b, limits) {
+		mm = bot.finishPonder(ctx, p, limits)
+	} else {
+		p.stop()
+		mm = bot.search(ctx, b, limits)
+	}
+	// Stopped before a single move was searched; any legal move beats none
+	if mm.Move.IsNull() {
+		mm.Move = b.GenerateAllMoves()[0]
+	}
+
+	bot.info.Hashfull = bot.tt.Stats().Hashfull
+	bot.info = bot.info.WithRates()
+	bot.Finish(bot.info)
+
+	probes, hits, egHits := bot.probes, bot.hits, bot.egHits
+	for _, h := range bot.helpers {
+		probes += h.probes
+		hits += h.hits
+		egHits += h.egHits
+	}
+	bot.Logf("bestmove %s score %s depth %d nodes %d time %s hash hits %d of %d endgame hits %d",
+		mm.Move.String(), engine.FormatScore(mm.Value), bot.info.Depth, bot.info.Nodes, bot.info.Time.Round(time.Millisecond), hits, probes, egHits)
+	return mm.Move, nil
+}
+
+func (bot *Bot) Info() engine.SearchInfo { return bot.info }
+
+// SetThreads sets how many threads each search uses, at least one.
+func (bot *Bot) SetThreads(n int) { bot.Threads = max(n, 1) }
+
+// SetOption turns one of the search's Features on or off: "pvs", "lmr" or
+// "aspiration", set to "on" or "off". Options "egdb" and "dtw" name a file of
+// endgame win/loss/draw or distance tables to consult, and "book" an opening
+// book, or "" for none.
+func (bot *Bot) SetOption(name, value string) (err error) {
+	switch name {
+	case "egdb":
+		bot.Endgame = nil
+		if value != "" {
+			bot.Endgame, err = endgame.Open(value)
+		}
+		return err
+	case "dtw":
+		bot.Distances = nil
+		if value != "" {
+			bot.Distances, err = endgame.OpenDistances(value)
+		}
+		return err
+	case "book":
+		bot.Book = nil
+		if value != "" {
+			bot.Book, err = book.Open(value)
+		}
+		return err
+	}
+	var feature *bool
+	switch name {
+	case "pvs":
+		feature = &bot.Features.PVS
+	case "lmr":
+		feature = &bot.Features.LMR
+	case "aspiration":
+		feature = &bot.Features.Aspiration
+	default:
+		return fmt.Errorf("negascout: %w %q", engine.ErrUnknownOption, name)
+	}
+	switch value {
+	case "on":
+		*feature = true
+	case "off":
+		*feature = false
+	default:
+		return fmt.Errorf("negascout: option %s wants on or off, not %q", name, value)
+	}
+	return nil
+}
+
+// Reset stops any pondering and clears the transposition table and move
+// ordering statistics for a new game.
+func (bot *Bot) Reset() {
+	bot.StopPondering()
+	bot.tt.Clear()
+	bot.order = heuristics{}
+	for _, h := range bot.helpers {
+		h.order = heuristics{}
+	}
+}
@@ -0,0 +1,53 @@
+package negascout
+
+import (
+	"context"
+	"testing"
+
+	"myproject/engine"
+)
+
+// BenchmarkBench searches the bench positions and reports the nodes it took,
+// which is the figure to compare when changing move ordering or pruning.
+func BenchmarkBench(b *testing.B) {
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	var nodes int
+	for i := 0; i < b.N; i++ {
+		results, err := engine.Bench(context.Background(), bot, engine.BenchDepth)
+		if err != nil {
+			b.Fatal(err)
+		}
+		nodes = 0
+		for _, r := range results {
+			nodes += r.Info.Nodes
+		}
+	}
+	b.ReportMetric(float64(nodes), "nodes/op")
+}
+
+// The search with one thread is deterministic, so the bench must count the
+// same nodes every time it runs.
+func TestBenchRepeats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("searches the bench positions twice")
+	}
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	var first []engine.BenchResult
+	for run := 0; run < 2; run++ {
+		results, err := engine.Bench(context.Background(), bot, engine.BenchDepth-2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first == nil {
+			first = results
+			continue
+		}
+		for i, r := range results {
+			if r.Info.Nodes != first[i].Info.Nodes || !r.Move.Equals(first[i].Move) {
+				t.Errorf("%s: %s in %d nodes, then %s in %d", r.FEN, first[i].Move, first[i].Info.Nodes, r.Move, r.Info.Nodes)
+			}
+		}
+	}
+}
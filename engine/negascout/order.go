@@ -0,0 +1,196 @@
+package negascout
+
+import (
+	"math/bits"
+
+	"myproject/checkers"
+)
+
+// Move ordering. A node tries the table's move first, since it often refutes
+// the node on its own and then the other moves need not be scored at all.
+// The rest are scored once and picked best first: captures by how much they
+// take, promotions, the killer moves that caused cutoffs at the same ply, the
+// reply that last refuted the opponent's move, and then the quiet moves by
+// how often they have caused cutoffs anywhere in the tree.
+const (
+	scoreCapture   = 1 << 30
+	scorePromotion = 1 << 29
+	scoreKiller    = 1 << 28 // the second killer scores one less
+	scoreCounter   = 1 << 27
+	maxHistory     = 1 << 26 // history scores are halved when one reaches this
+)
+
+const numKillers = 2
+
+// Picker stages
+const (
+	stageTT = iota
+	stageScore
+	stagePick
+)
+
+// heuristics is what the search learns about quiet moves as it goes. Moves
+// are kept packed, and squares are bit indexes x+y*8.
+type heuristics struct {
+	killers [][numKillers]uint64 // per ply from the root
+	history [2][64][64]int32     // [side][from][to]: cutoffs weighted by depth
+	counter [2][64][64]uint64    // [side][from][to] of the opponent's move: the reply that refuted it
+}
+
+// clear forgets the killers, which belong to the last root, and ages the
+// history so the new search soon outweighs it.
+func (h *heuristics) clear() {
+	clear(h.killers)
+	h.age(0)
+	h.age(1)
+}
+
+// age halves side's history scores.
+func (h *heuristics) age(side int) {
+	for f := range h.history[side] {
+		for t := range h.history[side][f] {
+			h.history[side][f][t] /= 2
+		}
+	}
+}
+
+func side(b *checkers.Board) int {
+	if b.BitBoard.IsRedTurn {
+		return 0
+	}
+	return 1
+}
+
+func from(m *checkers.Move) int { return m.FromX + m.FromY*8 }
+func to(m *checkers.Move) int   { return m.ToX + m.ToY*8 }
+
+// cutoff records that the quiet move m refuted the node at ply with depth to go.
+func (h *heuristics) cutoff(b *checkers.Board, ply int, m *checkers.Move, depth float64) {
+	if m.IsJump {
+		return
+	}
+	for len(h.killers) <= ply {
+		h.killers = append(h.killers, [numKillers]uint64{})
+	}
+	p := m.Pack()
+	if k := &h.killers[ply]; k[0] != p {
+		copy(k[1:], k[:numKillers-1])
+		k[0] = p
+	}
+
+	s := side(b)
+	hist := &h.history[s][from(m)][to(m)]
+	*hist += int32(depth * depth)
+	if *hist >= maxHistory {
+		h.age(s)
+	}
+
+	if last, ok := b.LastMove(); ok {
+		h.counter[s][from(&last)][to(&last)] = p
+	}
+}
+
+// movePicker hands out the moves of one node in order.
+type movePicker struct {
+	moves  []checkers.Move
+	scores []int32
+	tt     checkers.Move
+	stage  int
+	next   int
+}
+
+// newPicker starts picking the moves of the node at ply, the table's move tt
+// first if it is one of them.
+func (bot *Bot) newPicker(b *checkers.Board, ply int, tt checkers.Move) *movePicker {
+	for len(bot.pickers) <= ply {
+		bot.pickers = append(bot.pickers, &movePicker{})
+	}
+	mp := bot.pickers[ply]
+	*mp = movePicker{moves: bot.movesAt(b), scores: mp.scores[:0], tt: tt}
+	return mp
+}
+
+// Next returns the next move to try, or false when there are none left. The
+// table's move comes back marked IsSuperior.
+func (mp *movePicker) Next(b *checkers.Board, h *heuristics, ply int) (checkers.Move, bool) {
+	switch mp.stage {
+	case stageTT:
+		mp.stage = stageScore
+		if !mp.tt.IsNull() {
+			for i := range mp.moves {
+				if mp.moves[i].Equals(mp.tt) {
+					mp.moves[0], mp.moves[i] = mp.moves[i], mp.moves[0]
+					mp.next = 1
+					m := mp.moves[0]
+					m.IsSuperior = true
+					return m, true
+				}
+			}
+		}
+		fallthrough
+	case stageScore:
+		mp.stage = stagePick
+		mp.score(b, h, ply)
+	}
+	if mp.next >= len(mp.moves) {
+		return checkers.Move{}, false
+	}
+
+	// Selection sort one step at a time, since a cutoff often comes early
+	best := mp.next
+	for i := mp.next + 1; i < len(mp.moves); i++ {
+		if mp.scores[i] > mp.scores[best] {
+			best = i
+		}
+	}
+	mp.moves[mp.next], mp.moves[best] = mp.moves[best], mp.moves[mp.next]
+	mp.scores[mp.next], mp.scores[best] = mp.scores[best], mp.scores[mp.next]
+	mp.next++
+	return mp.moves[mp.next-1], true
+}
+
+// quiet reports whether the move handed out last was an ordinary quiet move,
+// rather than the table's move, a capture, a promotion, a killer or a
+// countermove.
+func (mp *movePicker) quiet() bool {
+	return mp.stage == stagePick && mp.next > 0 && mp.scores[mp.next-1] < scoreCounter
+}
+
+// score rates the moves not yet handed out.
+func (mp *movePicker) score(b *checkers.Board, h *heuristics, ply int) {
+	s := side(b)
+	var killers [numKillers]uint64
+	if ply < len(h.killers) {
+		killers = h.killers[ply]
+	}
+	var counter uint64
+	if last, ok := b.LastMove(); ok {
+		counter = h.counter[s][from(&last)][to(&last)]
+	}
+
+	for len(mp.scores) < len(mp.moves) {
+		mp.scores = append(mp.scores, 0)
+	}
+	for i := mp.next; i < len(mp.moves); i++ {
+		m := &mp.moves[i]
+		switch {
+		case m.IsJump:
+			// Longer sequences first, and kings are worth more than men
+			mp.scores[i] = scoreCapture + int32(m.Jumps)*16 + int32(bits.OnesCount64(m.Captured&b.BitBoard.King))
+		case m.Promotes():
+			mp.scores[i] = scorePromotion
+		default:
+			p := m.Pack()
+			switch p {
+			case killers[0]:
+				mp.scores[i] = scoreKiller
+			case killers[1]:
+				mp.scores[i] = scoreKiller - 1
+			case counter:
+				mp.scores[i] = scoreCounter
+			default:
+				mp.scores[i] = h.history[s][from(m)][to(m)]
+			}
+		}
+	}
+}
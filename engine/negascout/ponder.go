@@ -0,0 +1,121 @@
+package negascout
+
+import (
+	"context"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// ponder is a search of the position after the opponent's expected reply,
+// running while the opponent thinks.
+type ponder struct {
+	position checkers.BitBoard
+	cancel   context.CancelFunc
+	done     chan engine.Position
+}
+
+// Ponder guesses the opponent's reply on b from the principal variation and
+// starts searching the position it leads to.
+func (bot *Bot) Ponder(b *checkers.Board) {
+	bot.StopPondering()
+	reply, ok := bot.expectedReply(b)
+	if !ok {
+		return
+	}
+	board := b.Clone()
+	reply.MakeMove(board)
+	board.PlyCount++
+	if board.Result().IsOver() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &ponder{position: *board.BitBoard, cancel: cancel, done: make(chan engine.Position, 1)}
+	bot.mu.Lock()
+	bot.pondering = p
+	bot.mu.Unlock()
+	go func() {
+		bot.quiet = true
+		mm := bot.search(ctx, board, engine.SearchLimits{Infinite: true})
+		bot.quiet = false
+		p.done <- mm
+	}()
+}
+
+// expectedReply returns the reply the last search's principal variation
+// expects on b, falling back on the best move the table holds for b.
+func (bot *Bot) expectedReply(b *checkers.Board) (checkers.Move, bool) {
+	var guess checkers.Move
+	last, _ := b.LastMove()
+	if pv := bot.info.PV; len(pv) >= 2 && last.Equals(pv[0]) {
+		guess = pv[1]
+	} else if e, ok := bot.tt.Probe(b.BitBoard); ok {
+		guess = e.Pos.Move
+	}
+	if guess.IsNull() {
+		return checkers.Move{}, false
+	}
+	for _, m := range b.GenerateAllMoves() {
+		if m.Equals(guess) {
+			return m, true
+		}
+	}
+	return checkers.Move{}, false
+}
+
+// StopPondering ends any pondering and waits for it to wind up. The lock is
+// held throughout so a ChooseMove cannot start searching in the meantime.
+func (bot *Bot) StopPondering() {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bot.pondering.stop()
+	bot.pondering = nil
+}
+
+// takePonder hands over the running ponder search, if any, to the caller.
+func (bot *Bot) takePonder() *ponder {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	p := bot.pondering
+	bot.pondering = nil
+	return p
+}
+
+// stop cancels the ponder search and waits for it.
+func (p *ponder) stop() {
+	if p == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+// hit reports whether p is searching b and can simply carry on: the search
+// has to be told when to stop, so the limits need a time or to be infinite.
+func (p *ponder) hit(b *checkers.Board, limits engine.SearchLimits) bool {
+	if p == nil || *b.BitBoard != p.position {
+		return false
+	}
+	return limits.Infinite || engine.NewTimeManager(limits, time.Now()).Soft() > 0
+}
+
+// finishPonder lets the ponder search run on for the time planned for this
+// move, counted from now, and returns its result.
+func (bot *Bot) finishPonder(ctx context.Context, p *ponder, limits engine.SearchLimits) engine.Position {
+	var timeout <-chan time.Time
+	if soft := engine.NewTimeManager(limits, time.Now()).Soft(); soft > 0 && !limits.Infinite {
+		timer := time.NewTimer(soft)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case mm := <-p.done:
+		return mm
+	case <-ctx.Done():
+	case <-timeout:
+	}
+	p.cancel()
+	return <-p.done
+}
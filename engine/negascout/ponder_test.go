@@ -0,0 +1,118 @@
+package negascout
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// ponderAfterMove has bot choose and play a move from the start, then ponder
+// on the reply it expects, which it returns.
+func ponderAfterMove(t *testing.T, bot *Bot) (*checkers.Board, checkers.Move) {
+	t.Helper()
+	b := checkers.NewBoard()
+	m, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.MakeMove(b)
+	b.PlyCount++
+	reply, ok := bot.expectedReply(b)
+	if !ok {
+		t.Fatal("no reply expected after the search")
+	}
+	bot.Ponder(b)
+	if bot.pondering == nil {
+		t.Fatal("Ponder started no search")
+	}
+	return b, reply
+}
+
+// The ponder search has been running for a while by the time the move is
+// asked for, and a hit carries it on for the move time, so it reports the
+// whole time it took.
+const ponderHead = 300 * time.Millisecond
+
+func TestPonderHit(t *testing.T) {
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	b, reply := ponderAfterMove(t, bot)
+	time.Sleep(ponderHead)
+
+	reply.MakeMove(b)
+	b.PlyCount++
+	m, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{MoveTime: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isLegal(b, m) {
+		t.Fatalf("chose %s, which is not legal", m)
+	}
+	if took := bot.Info().Time; took < ponderHead {
+		t.Fatalf("the search took %v, so was not the ponder search carried on", took)
+	}
+	if bot.pondering != nil {
+		t.Fatal("the ponder search was left running")
+	}
+}
+
+func TestPonderMiss(t *testing.T) {
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	before := runtime.NumGoroutine()
+	b, reply := ponderAfterMove(t, bot)
+	time.Sleep(ponderHead)
+
+	var other checkers.Move
+	for _, m := range b.GenerateAllMoves() {
+		if !m.Equals(reply) {
+			other = m
+			break
+		}
+	}
+	other.MakeMove(b)
+	b.PlyCount++
+	m, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{MoveTime: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isLegal(b, m) {
+		t.Fatalf("chose %s, which is not legal", m)
+	}
+	if took := bot.Info().Time; took >= ponderHead {
+		t.Fatalf("the search took %v, so was the ponder search on the wrong position", took)
+	}
+	if bot.pondering != nil {
+		t.Fatal("the ponder search was left running")
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("%d goroutines before pondering and %d after the move", before, after)
+	}
+}
+
+func TestStopPonderingIdle(t *testing.T) {
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	start := time.Now()
+	bot.StopPondering()
+	if _, err := bot.ChooseMove(context.Background(), checkers.NewBoard(), engine.SearchLimits{Depth: 4}); err != nil {
+		t.Fatal(err)
+	}
+	bot.StopPondering()
+	bot.StopPondering()
+	if took := time.Since(start); took > time.Second {
+		t.Fatalf("stopping with nothing to stop took %v", took)
+	}
+
+	// Stopping a running ponder search returns once it has wound up
+	ponderAfterMove(t, bot)
+	start = time.Now()
+	bot.StopPondering()
+	if took := time.Since(start); took > time.Second || bot.pondering != nil {
+		t.Fatalf("stopping the ponder search took %v", took)
+	}
+}
@@ -0,0 +1,81 @@
+package negascout
+
+import (
+	"context"
+	"testing"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+func TestMultiPV(t *testing.T) {
+	const depth = 8
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	// A bench position whose best move, 22-18, stands well clear of the rest
+	b, err := checkers.NewBoardFromFEN("W:W17,19,21,22,23,25,26,27,28,29,31,32:B1,2,3,4,5,6,7,10,12,13,16,20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: depth, MultiPV: 3}); err != nil {
+		t.Fatal(err)
+	}
+	lines := bot.Info().Lines
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	for i, l := range lines {
+		if len(l.PV) == 0 || !isLegal(b, l.PV[0]) {
+			t.Fatalf("line %d, %s, does not start with a legal move", i+1, l)
+		}
+		for _, prev := range lines[:i] {
+			if prev.PV[0].Equals(l.PV[0]) {
+				t.Fatalf("%s is ranked twice", l.PV[0])
+			}
+		}
+		if i > 0 && l.Score > lines[i-1].Score {
+			t.Fatalf("line %d scores %s, more than the line before it", i+1, engine.FormatScore(l.Score))
+		}
+	}
+
+	single := NewBot()
+	single.SetLogger(engine.NopLogger)
+	m, err := single.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: depth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Equals(lines[0].PV[0]) {
+		t.Fatalf("the best line is %s, but a single-line search plays %s", lines[0], m)
+	}
+}
+
+func TestForcedWinScore(t *testing.T) {
+	// Two kings against one in the corner, won in three plies: the endgame
+	// distance tables agree
+	b, err := checkers.NewBoardFromFEN("B:WK1:BK10,K9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot := NewBot()
+	bot.SetLogger(engine.NopLogger)
+	m, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bot.Info().Score; got != engine.WinScore-3 {
+		t.Fatalf("scored %s, want win in 3", engine.FormatScore(got))
+	}
+
+	// The principal variation plays the win out
+	pv := bot.Info().PV
+	if len(pv) != 3 || !pv[0].Equals(m) {
+		t.Fatalf("chose %s with the line %s, want three plies", m, engine.FormatPV(pv))
+	}
+	for _, move := range pv {
+		move.MakeMove(b)
+		b.PlyCount++
+	}
+	if r := b.Result(); r.Status != checkers.RedWins {
+		t.Fatalf("the line ends with %s", r)
+	}
+}
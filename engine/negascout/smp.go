@@ -0,0 +1,59 @@
+package negascout
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// startHelpers runs Threads-1 extra searches on copies of b, all sharing the
+// transposition table ("lazy SMP"). They have no limits of their own: the
+// returned function stops them, waits for them to finish and returns the
+// number of nodes they searched. Every helper starts ahead of the main search,
+// alternately one and two iterations deeper, so the threads spread out over
+// different depths and fill the table with results the main search can use.
+func (bot *Bot) startHelpers(ctx context.Context, b *checkers.Board) (wait func() int) {
+	n := max(bot.Threads-1, 0)
+	for len(bot.helpers) < n {
+		bot.helpers = append(bot.helpers, &Bot{
+			tt:          bot.tt,
+			quiet:       true,
+			depthOffset: 1 + len(bot.helpers)%2,
+		})
+	}
+	bot.helpers = bot.helpers[:n]
+	for _, h := range bot.helpers {
+		h.Features = bot.Features
+		h.Endgame = bot.Endgame
+		h.Distances = bot.Distances
+	}
+	if n == 0 {
+		return func() int { return 0 }
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	boards := make([]*checkers.Board, n)
+	for i, h := range bot.helpers {
+		boards[i] = b.Clone()
+		boards[i].NodeBudget = math.MaxInt
+		h.probes, h.hits, h.egHits = 0, 0, 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.recursiveDeepening(ctx, boards[i], engine.SearchLimits{Infinite: true})
+		}()
+	}
+	return func() int {
+		cancel()
+		wg.Wait()
+		nodes := 0
+		for _, hb := range boards {
+			nodes += math.MaxInt - hb.NodeBudget
+		}
+		return nodes
+	}
+}
@@ -0,0 +1,50 @@
+package negascout
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// isLegal reports whether m can be played on b.
+func isLegal(b *checkers.Board, m checkers.Move) bool {
+	for _, legal := range b.GenerateAllMoves() {
+		if legal.Equals(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestThreads(t *testing.T) {
+	for _, fen := range []string{
+		"B:W21,22,23,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,10,11,12",
+		"B:W14,15,22,23:BK9", // three ways to take two men
+	} {
+		b, err := checkers.NewBoardFromFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bot := NewBot()
+		bot.SetLogger(engine.NopLogger)
+		bot.SetThreads(4)
+		before := runtime.NumGoroutine()
+		m, err := bot.ChooseMove(context.Background(), b, engine.SearchLimits{Depth: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isLegal(b, m) {
+			t.Fatalf("%s: chose %s, which is not legal", fen, m)
+		}
+		if len(bot.helpers) != 3 {
+			t.Fatalf("%s: searched with %d helpers, want 3", fen, len(bot.helpers))
+		}
+		// The search waits for its helpers before returning
+		if after := runtime.NumGoroutine(); after > before {
+			t.Fatalf("%s: %d goroutines before the search and %d after", fen, before, after)
+		}
+	}
+}
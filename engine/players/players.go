@@ -0,0 +1,29 @@
+// Package players builds any kind of engine.Engine by name, so front ends can
+// let the user pick who plays each colour.
+package players
+
+import (
+	"fmt"
+	"strings"
+
+	"myproject/engine"
+	"myproject/engine/montecarlo"
+	"myproject/engine/negascout"
+)
+
+// Names lists the players New knows about.
+var Names = []string{"human", "negascout", "montecarlo"}
+
+// New returns a fresh player. A human player has no Input yet; the front end
+// using it sets one.
+func New(name string) (engine.Engine, error) {
+	switch name {
+	case "human":
+		return engine.NewHuman(nil), nil
+	case "negascout":
+		return negascout.NewBot(), nil
+	case "montecarlo":
+		return montecarlo.NewMBot(), nil
+	}
+	return nil, fmt.Errorf("unknown player %q (want one of %s)", name, strings.Join(Names, ", "))
+}
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"strings"
+
+	"myproject/checkers"
+)
+
+// Line is a root move's evaluation: its score and the moves expected to follow.
+type Line struct {
+	Score float64
+	PV    []checkers.Move
+}
+
+func (l Line) String() string {
+	return FormatScore(l.Score) + " " + FormatPV(l.PV)
+}
+
+// FormatPV writes a variation in square notation, such as "11-15 22-18 15x22".
+func FormatPV(pv []checkers.Move) string {
+	parts := make([]string, len(pv))
+	for i := range pv {
+		parts[i] = pv[i].String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// PVTable collects the principal variation while searching: line[ply] is the
+// best line found so far from the node at that ply, built from the line of
+// the child it came from, so the root's line is the whole variation.
+type PVTable struct {
+	lines [][]checkers.Move
+}
+
+// Clear empties the line at ply; each node does this on entry.
+func (t *PVTable) Clear(ply int) {
+	for len(t.lines) <= ply+1 {
+		t.lines = append(t.lines, make([]checkers.Move, 0, 16))
+	}
+	t.lines[ply] = t.lines[ply][:0]
+}
+
+// Update makes m followed by the child's line the best line at ply.
+func (t *PVTable) Update(ply int, m checkers.Move) {
+	t.lines[ply] = append(append(t.lines[ply][:0], m), t.lines[ply+1]...)
+}
+
+// Line returns a copy of the line at ply.
+func (t *PVTable) Line(ply int) []checkers.Move {
+	if ply >= len(t.lines) {
+		return nil
+	}
+	return append([]checkers.Move(nil), t.lines[ply]...)
+}
+
+// MaxPV bounds the length of a reported variation.
+const MaxPV = 24
+
+// ExtendPV follows pv on with the best moves the table holds, up to MaxPV
+// moves in all, since a line collected by the search stops wherever a table
+// entry cut it short. It stops at the end of the game or a repetition. b is
+// left as it was.
+func (tt *TransTable) ExtendPV(b *checkers.Board, pv []checkers.Move) []checkers.Move {
+	played := 0
+	for _, m := range pv {
+		b.Save()
+		m.MakeMove(b)
+		played++
+	}
+	for len(pv) < MaxPV && !b.Result().IsOver() && b.Repetitions() <= 1 {
+		e, ok := tt.Probe(b.BitBoard)
+		if !ok || e.Pos.Move.IsNull() {
+			break
+		}
+		var next checkers.Move
+		for _, m := range b.GenerateAllMoves() {
+			if m.Equals(e.Pos.Move) {
+				next = m
+			}
+		}
+		if next.IsNull() {
+			break
+		}
+		pv = append(pv, next)
+		b.Save()
+		next.MakeMove(b)
+		played++
+	}
+	for ; played > 0; played-- {
+		b.Load()
+	}
+	return pv
+}
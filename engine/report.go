@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Logger takes the lines an engine writes about its searches. A *log.Logger
+// will do, so they can go to a file as easily as to the terminal.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// StderrLogger writes to standard error, which is where engines log by default.
+var StderrLogger Logger = log.New(os.Stderr, "", 0)
+
+// NopLogger throws everything away, silencing an engine.
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...any) {}
+
+// Reporting is implemented by engines that publish their progress while
+// they search.
+type Reporting interface {
+	// Subscribe has h called with the search's state after every finished
+	// iteration, from the searching goroutine, until unsubscribe is called.
+	Subscribe(h func(SearchInfo)) (unsubscribe func())
+	// Current returns the state of the running search, or of the last one
+	// if none is running. It may be called from any goroutine.
+	Current() SearchInfo
+	// SetLogger sends the engine's log lines to l instead of standard error.
+	SetLogger(l Logger)
+}
+
+// Reporter implements Reporting for an engine to embed. The zero value is
+// ready to use and logs to standard error.
+type Reporter struct {
+	mu       sync.Mutex
+	handlers []subscriber
+	nextID   int
+	current  SearchInfo
+	started  time.Time
+	running  bool
+	nodes    atomic.Int64 // counted so far in the running search
+	logger   Logger
+}
+
+type subscriber struct {
+	id int
+	h  func(SearchInfo)
+}
+
+func (r *Reporter) Subscribe(h func(SearchInfo)) (unsubscribe func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := r.nextID
+	r.handlers = append(r.handlers, subscriber{id, h})
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i, s := range r.handlers {
+			if s.id == id {
+				r.handlers = append(r.handlers[:i], r.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (r *Reporter) SetLogger(l Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = l
+}
+
+// Logf writes a line to the engine's logger.
+func (r *Reporter) Logf(format string, v ...any) {
+	r.mu.Lock()
+	l := r.logger
+	r.mu.Unlock()
+	if l == nil {
+		l = StderrLogger
+	}
+	l.Printf(format, v...)
+}
+
+// Start marks the beginning of a search.
+func (r *Reporter) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = SearchInfo{}
+	r.started = time.Now()
+	r.running = true
+	r.nodes.Store(0)
+}
+
+// Progress records how many nodes the running search has visited. It is
+// cheap enough to call every few thousand nodes.
+func (r *Reporter) Progress(nodes int) {
+	r.nodes.Store(int64(nodes))
+}
+
+// Publish records info as the search's latest state, passes it to the
+// subscribers and logs it.
+func (r *Reporter) Publish(info SearchInfo) {
+	r.mu.Lock()
+	r.current = info
+	r.nodes.Store(int64(info.Nodes))
+	handlers := append([]subscriber(nil), r.handlers...)
+	r.mu.Unlock()
+	for _, s := range handlers {
+		s.h(info)
+	}
+	r.Logf("info %s", info)
+}
+
+// Finish marks the end of a search, whose final state is info.
+func (r *Reporter) Finish(info SearchInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = info
+	r.running = false
+}
+
+func (r *Reporter) Current() SearchInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := r.current
+	if r.running {
+		info.Time = time.Since(r.started)
+		info.Nodes = int(r.nodes.Load())
+		info.NPS = nps(info.Nodes, info.Time)
+	}
+	return info
+}
+
+// nps returns the search speed in nodes per second.
+func nps(nodes int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int(float64(nodes) / elapsed.Seconds())
+}
+
+// WithRates fills in NPS from Nodes and Time.
+func (i SearchInfo) WithRates() SearchInfo {
+	i.NPS = nps(i.Nodes, i.Time)
+	return i
+}
+
+func (i SearchInfo) String() string {
+	return fmt.Sprintf("depth %d seldepth %d score %s nodes %d nps %d hashfull %d time %s pv %s",
+		i.Depth, i.SelDepth, FormatScore(i.Score), i.Nodes, i.NPS, i.Hashfull, i.Time.Round(time.Millisecond), FormatPV(i.PV))
+}
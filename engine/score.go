@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// WinScore is the score of a position whose side to move has already won.
+// A win n plies from the root of the search scores WinScore-n and a loss
+// -(WinScore-n), so the search prefers quicker wins and slower losses. Inside
+// the transposition table the distance is counted from the stored position
+// instead, so an entry means the same wherever the position turns up.
+const WinScore = 1_000_000
+
+// MaxPly bounds the distance to the end of the game a score can express.
+const MaxPly = 10_000
+
+// KnownWin is added to the evaluation of a position an endgame database says
+// is won, and taken from one it says is lost. It ranks them above any
+// material advantage the evaluation can see but below forced results the
+// search has found, since the database does not say how far off the end is.
+const KnownWin = 5_000
+
+// LossScore is the score of the side to move having lost ply plies from the root.
+func LossScore(ply int) float64 {
+	return -(WinScore - float64(ply))
+}
+
+// IsDecided reports whether score is a forced win or loss.
+func IsDecided(score float64) bool {
+	return math.Abs(score) > WinScore-MaxPly && math.Abs(score) <= WinScore
+}
+
+// PliesToEnd returns how many plies from the root a decided score's game ends.
+func PliesToEnd(score float64) int {
+	return int(math.Round(WinScore - math.Abs(score)))
+}
+
+// ScoreToTT converts a score found at ply from the root to one relative to
+// the position, for storing.
+func ScoreToTT(score float64, ply int) float64 {
+	switch {
+	case !IsDecided(score):
+		return score
+	case score > 0:
+		return score + float64(ply)
+	}
+	return score - float64(ply)
+}
+
+// ScoreFromTT converts a stored score back to one relative to the root, for
+// the position found at ply.
+func ScoreFromTT(score float64, ply int) float64 {
+	switch {
+	case !IsDecided(score):
+		return score
+	case score > 0:
+		return score - float64(ply)
+	}
+	return score + float64(ply)
+}
+
+// FormatScore writes a score for people: "+0.41", or "win in 7" and
+// "loss in 6" counting plies.
+func FormatScore(score float64) string {
+	switch {
+	case !IsDecided(score):
+		return fmt.Sprintf("%+.2f", score)
+	case score > 0:
+		return fmt.Sprintf("win in %d", PliesToEnd(score))
+	}
+	return fmt.Sprintf("loss in %d", PliesToEnd(score))
+}
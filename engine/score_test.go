@@ -0,0 +1,46 @@
+package engine
+
+import "testing"
+
+func TestScoreTT(t *testing.T) {
+	tests := []struct {
+		score  float64
+		ply    int
+		stored float64
+	}{
+		{WinScore - 7, 3, WinScore - 4}, // a win 7 plies from the root is 4 from a node at ply 3
+		{LossScore(6), 2, LossScore(4)},
+		{WinScore - 1, 1, WinScore},
+		{0.37, 5, 0.37},
+		{-2.5, 5, -2.5},
+		{KnownWin + 1.2, 4, KnownWin + 1.2}, // the endgame database's scores carry no distance
+	}
+	for _, tt := range tests {
+		stored := ScoreToTT(tt.score, tt.ply)
+		if stored != tt.stored {
+			t.Errorf("ScoreToTT(%v, %d) = %v, want %v", tt.score, tt.ply, stored, tt.stored)
+		}
+		if back := ScoreFromTT(stored, tt.ply); back != tt.score {
+			t.Errorf("%v stored at ply %d comes back as %v", tt.score, tt.ply, back)
+		}
+	}
+
+	// The same stored entry reached at another ply is that much further off
+	if got := ScoreFromTT(ScoreToTT(WinScore-7, 3), 5); got != WinScore-9 {
+		t.Errorf("a win 4 plies from a node at ply 5 scores %v, want %v", got, WinScore-9)
+	}
+}
+
+func TestFormatScore(t *testing.T) {
+	for score, want := range map[float64]string{
+		0.41:         "+0.41",
+		-1.5:         "-1.50",
+		WinScore - 7: "win in 7",
+		LossScore(6): "loss in 6",
+		KnownWin + 1: "+5001.00",
+	} {
+		if got := FormatScore(score); got != want {
+			t.Errorf("FormatScore(%v) = %q, want %q", score, got, want)
+		}
+	}
+}
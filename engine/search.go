@@ -0,0 +1,27 @@
+// Package engine holds the types shared by the checkers bots.
+package engine
+
+import "myproject/checkers"
+
+// Position is a search result: the best move found, if any, and its score.
+type Position struct {
+	Move  checkers.Move
+	Value float64
+}
+
+type ByValue []Position
+
+func (a ByValue) Len() int           { return len(a) }
+func (a ByValue) Less(i, j int) bool { return a[i].Value > a[j].Value }
+func (a ByValue) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+const EXACT = 0
+const UPPERBOUND = 1
+const LOWERBOUND = 2
+
+// Entry is what the transposition table remembers about a position.
+type Entry struct {
+	Pos   Position
+	Depth float64
+	Typ   int
+}
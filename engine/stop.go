@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"myproject/checkers"
+)
+
+// pollInterval is how many nodes go by between looks at the clock and the context.
+const pollInterval = 1024
+
+// Stopper tells a running search when to give up: its node budget is spent,
+// its deadline has passed or its context was cancelled. The clock and the
+// context are only looked at every pollInterval nodes, since doing so at every
+// node would slow the search down noticeably.
+type Stopper struct {
+	ctx      context.Context
+	deadline time.Time // zero for no deadline
+	calls    int
+	stopped  bool
+	onPoll   func(b *checkers.Board)
+}
+
+// NewStopper returns a Stopper for a search that must end when ctx is
+// cancelled or, unless deadline is zero, when deadline passes.
+func NewStopper(ctx context.Context, deadline time.Time) *Stopper {
+	return &Stopper{ctx: ctx, deadline: deadline}
+}
+
+// Stop is called once per node and reports whether the search has to stop.
+// Once it has said so it keeps saying so.
+func (s *Stopper) Stop(b *checkers.Board) bool {
+	if s.stopped {
+		return true
+	}
+	if b.NodeBudget <= 0 {
+		s.stopped = true
+		return true
+	}
+	s.calls++
+	if s.calls%pollInterval == 0 {
+		if s.onPoll != nil {
+			s.onPoll(b)
+		}
+		s.stopped = s.ctx.Err() != nil || (!s.deadline.IsZero() && time.Now().After(s.deadline))
+	}
+	return s.stopped
+}
+
+// Stopped reports whether Stop has said to stop.
+func (s *Stopper) Stopped() bool {
+	return s.stopped
+}
+
+// OnPoll has f called every time the Stopper looks at the clock, so a search
+// can report its progress at the same pace.
+func (s *Stopper) OnPoll(f func(b *checkers.Board)) {
+	s.onPoll = f
+}
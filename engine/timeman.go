@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// movesToGoGuess is how many more moves a game is assumed to last when the
+// time control does not say.
+const movesToGoGuess = 30
+
+// clockMargin is kept back on the clock for the time it takes to report a move.
+const clockMargin = 50 * time.Millisecond
+
+// TimeManager decides how long to think about one move. The search must stop
+// at the hard limit, even mid-iteration. The soft limit is only looked at
+// between iterations: once it has passed, no new iteration is started. It
+// grows while the best move keeps changing, since that is when more time helps.
+type TimeManager struct {
+	start time.Time
+	base  time.Duration // the share of the clock planned for this move
+	soft  time.Duration
+	hard  time.Duration // 0 for no time limit
+}
+
+// NewTimeManager plans a search started at start under limits.
+func NewTimeManager(limits SearchLimits, start time.Time) *TimeManager {
+	tm := &TimeManager{start: start}
+	if limits.Infinite {
+		return tm
+	}
+	if limits.Clock > 0 {
+		movesToGo := limits.MovesToGo
+		if movesToGo <= 0 {
+			movesToGo = movesToGoGuess
+		}
+		usable := max(limits.Clock-clockMargin, limits.Clock/2)
+		tm.base = min(limits.Clock/time.Duration(movesToGo)+limits.Increment*3/4, usable)
+		tm.soft = tm.base
+		// Even an unstable search may not use more than a quarter of what is
+		// left, unless this is the last move before the time control
+		tm.hard = min(tm.base*4, max(usable/4, tm.base))
+		if movesToGo == 1 {
+			tm.hard = usable
+		}
+	}
+	if limits.MoveTime > 0 && (tm.hard == 0 || limits.MoveTime < tm.hard) {
+		// A fixed move time is used in full
+		tm.base, tm.soft, tm.hard = limits.MoveTime, limits.MoveTime, limits.MoveTime
+	}
+	return tm
+}
+
+// Deadline returns the hard limit as a time, or the zero time if there is none.
+func (tm *TimeManager) Deadline() time.Time {
+	if tm.hard == 0 {
+		return time.Time{}
+	}
+	return tm.start.Add(tm.hard)
+}
+
+// Soft returns how long the search plans to take, or 0 if it has no time limit.
+func (tm *TimeManager) Soft() time.Duration {
+	return tm.soft
+}
+
+// BestMoveChanged gives the search more time because a new iteration
+// preferred a different move than the last one.
+func (tm *TimeManager) BestMoveChanged() {
+	tm.soft = min(tm.soft+tm.base/2, tm.hard)
+}
+
+// PastSoft reports whether the soft limit has passed, so no new iteration
+// should be started.
+func (tm *TimeManager) PastSoft() bool {
+	return tm.hard != 0 && time.Since(tm.start) >= tm.soft
+}
+
+// TimeControl describes a game's time control: Initial on each clock at the
+// start, Increment added after every move and, if Moves is set, Initial added
+// again every Moves moves.
+type TimeControl struct {
+	Initial   time.Duration
+	Increment time.Duration
+	Moves     int
+}
+
+// ParseTimeControl reads a time control such as "5m+3s" (five minutes plus
+// three seconds a move), "40/10m" (forty moves in ten minutes) or "40/10m+5s".
+func ParseTimeControl(s string) (TimeControl, error) {
+	var tc TimeControl
+	rest := strings.TrimSpace(s)
+	if moves, after, ok := strings.Cut(rest, "/"); ok {
+		n, err := strconv.Atoi(moves)
+		if err != nil || n <= 0 {
+			return tc, fmt.Errorf("engine: bad move count in time control %q", s)
+		}
+		tc.Moves = n
+		rest = after
+	}
+	initial, inc, hasInc := strings.Cut(rest, "+")
+	var err error
+	if tc.Initial, err = time.ParseDuration(initial); err != nil || tc.Initial <= 0 {
+		return tc, fmt.Errorf("engine: bad time in time control %q", s)
+	}
+	if hasInc {
+		if tc.Increment, err = time.ParseDuration(inc); err != nil || tc.Increment < 0 {
+			return tc, fmt.Errorf("engine: bad increment in time control %q", s)
+		}
+	}
+	return tc, nil
+}
+
+func (tc TimeControl) String() string {
+	s := tc.Initial.String()
+	if tc.Moves > 0 {
+		s = strconv.Itoa(tc.Moves) + "/" + s
+	}
+	if tc.Increment > 0 {
+		s += "+" + tc.Increment.String()
+	}
+	return s
+}
+
+// Clock is one side's clock in a game played under a TimeControl.
+type Clock struct {
+	Control TimeControl
+	Left    time.Duration
+	played  int
+}
+
+// NewClock returns a clock set to the start of tc.
+func NewClock(tc TimeControl) *Clock {
+	return &Clock{Control: tc, Left: tc.Initial}
+}
+
+// Limits returns the search limits for the next move on this clock.
+func (c *Clock) Limits() SearchLimits {
+	l := SearchLimits{Clock: c.Left, Increment: c.Control.Increment}
+	if c.Control.Moves > 0 {
+		l.MovesToGo = c.Control.Moves - c.played%c.Control.Moves
+	}
+	return l
+}
+
+// Punch charges used to the clock once a move is played and adds any time
+// earned by it. It reports false if the flag fell before the move was made.
+func (c *Clock) Punch(used time.Duration) bool {
+	c.Left -= used
+	if c.Left < 0 {
+		return false
+	}
+	c.played++
+	c.Left += c.Control.Increment
+	if c.Control.Moves > 0 && c.played%c.Control.Moves == 0 {
+		c.Left += c.Control.Initial
+	}
+	return true
+}
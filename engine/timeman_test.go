@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	tests := []struct {
+		s    string
+		want TimeControl
+	}{
+		{"5m+3s", TimeControl{Initial: 5 * time.Minute, Increment: 3 * time.Second}},
+		{"40/10m", TimeControl{Initial: 10 * time.Minute, Moves: 40}},
+		{" 40/10m+5s ", TimeControl{Initial: 10 * time.Minute, Increment: 5 * time.Second, Moves: 40}},
+		{"90s", TimeControl{Initial: 90 * time.Second}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeControl(tt.s)
+		if err != nil {
+			t.Errorf("%q: %v", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.s, got, tt.want)
+		}
+		if again, err := ParseTimeControl(got.String()); err != nil || again != got {
+			t.Errorf("%q: %s reads back as %+v, %v", tt.s, got, again, err)
+		}
+	}
+	for _, s := range []string{"", "5", "0s", "-1m", "x/5m", "0/5m", "5m+", "5m+-1s", "5m+3"} {
+		if _, err := ParseTimeControl(s); err == nil {
+			t.Errorf("%q parsed without an error", s)
+		}
+	}
+}
+
+func TestTimeManagerLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     SearchLimits
+		soft, hard time.Duration
+	}{
+		{"sudden death", SearchLimits{Clock: time.Minute}, 2 * time.Second, 8 * time.Second},
+		{"increment", SearchLimits{Clock: time.Minute, Increment: 4 * time.Second}, 5 * time.Second, 14987500 * time.Microsecond},
+		{"moves to go", SearchLimits{Clock: 30 * time.Second, MovesToGo: 10}, 3 * time.Second, 7487500 * time.Microsecond},
+		{"last move before the control", SearchLimits{Clock: 10 * time.Second, MovesToGo: 1}, 9950 * time.Millisecond, 9950 * time.Millisecond},
+		{"nearly flagged", SearchLimits{Clock: 80 * time.Millisecond}, 2666666 * time.Nanosecond, 10 * time.Millisecond},
+		{"move time", SearchLimits{MoveTime: 500 * time.Millisecond}, 500 * time.Millisecond, 500 * time.Millisecond},
+		{"move time within the clock's", SearchLimits{Clock: time.Minute, MoveTime: 500 * time.Millisecond}, 500 * time.Millisecond, 500 * time.Millisecond},
+		{"move time past the clock's", SearchLimits{Clock: time.Minute, MoveTime: 20 * time.Second}, 2 * time.Second, 8 * time.Second},
+		{"depth only", SearchLimits{Depth: 5}, 0, 0},
+		{"infinite", SearchLimits{Infinite: true, MoveTime: time.Second}, 0, 0},
+	}
+	start := time.Now()
+	for _, tt := range tests {
+		tm := NewTimeManager(tt.limits, start)
+		if tm.Soft() != tt.soft || tm.hard != tt.hard {
+			t.Errorf("%s: soft %v, hard %v, want %v and %v", tt.name, tm.Soft(), tm.hard, tt.soft, tt.hard)
+		}
+		if tt.limits.Clock > 0 && tm.hard > tt.limits.Clock {
+			t.Errorf("%s: hard limit %v is more than the %v left", tt.name, tm.hard, tt.limits.Clock)
+		}
+		if deadline := tm.Deadline(); (tt.hard == 0) != deadline.IsZero() || (tt.hard != 0 && !deadline.Equal(start.Add(tt.hard))) {
+			t.Errorf("%s: deadline %v after the start", tt.name, deadline.Sub(start))
+		}
+	}
+}
+
+func TestBestMoveChanged(t *testing.T) {
+	tm := NewTimeManager(SearchLimits{Clock: time.Minute}, time.Now())
+	tm.BestMoveChanged()
+	if tm.Soft() != 3*time.Second {
+		t.Fatalf("one change gives a soft limit of %v, want 3s", tm.Soft())
+	}
+	for i := 0; i < 20; i++ {
+		tm.BestMoveChanged()
+	}
+	if tm.Soft() != tm.hard {
+		t.Fatalf("many changes give a soft limit of %v, want the hard limit %v", tm.Soft(), tm.hard)
+	}
+}
+
+func TestClockPunch(t *testing.T) {
+	tc, err := ParseTimeControl("2/1m+1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClock(tc)
+	steps := []struct {
+		used      time.Duration
+		left      time.Duration
+		movesToGo int
+	}{
+		{10 * time.Second, 51 * time.Second, 1},
+		{time.Second, 111 * time.Second, 2}, // the control tops the clock up
+		{20 * time.Second, 92 * time.Second, 1},
+	}
+	if l := c.Limits(); l.Clock != time.Minute || l.MovesToGo != 2 || l.Increment != time.Second {
+		t.Fatalf("limits at the start %+v", l)
+	}
+	for i, s := range steps {
+		if !c.Punch(s.used) {
+			t.Fatalf("move %d: the flag fell", i+1)
+		}
+		if l := c.Limits(); l.Clock != s.left || l.MovesToGo != s.movesToGo {
+			t.Fatalf("move %d: %v left with %d to go, want %v with %d", i+1, l.Clock, l.MovesToGo, s.left, s.movesToGo)
+		}
+	}
+	if c.Punch(93 * time.Second) {
+		t.Fatal("running over the time left did not flag")
+	}
+
+	// Without a move count the clock only gains the increment
+	c = NewClock(TimeControl{Initial: time.Second, Increment: 100 * time.Millisecond})
+	if !c.Punch(time.Second) || c.Left != 100*time.Millisecond || c.Limits().MovesToGo != 0 {
+		t.Fatalf("using the whole clock leaves %v", c.Left)
+	}
+}
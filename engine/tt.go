@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+
+	"myproject/checkers"
+)
+
+// DefaultHashMB is the transposition table size the bots start with.
+const DefaultHashMB = 64
+
+// TransTable is a fixed-size transposition table keyed by Zobrist hash. Each
+// bucket has a depth-preferred slot, which keeps the deepest result for the
+// current search, and an always-replace slot for everything else. Entries from
+// earlier searches are recognised by their generation and overwritten first,
+// so nothing has to be swept between moves.
+//
+// The table is shared by all search threads without locks. Each slot is a few
+// words written one at a time, and the key is stored XORed with the others;
+// a slot half written by one thread while another reads it fails the key
+// check and counts as a miss.
+type TransTable struct {
+	buckets    []ttBucket
+	generation uint8
+}
+
+type ttSlot struct {
+	check atomic.Uint64 // key ^ value ^ meta ^ move
+	value atomic.Uint64 // math.Float64bits of the score
+	meta  atomic.Uint64 // depth, bound type, generation and the used bit
+	move  atomic.Uint64 // the best move, packed
+}
+
+type ttBucket struct {
+	deep   ttSlot // depth-preferred
+	recent ttSlot // always-replace
+}
+
+// Layout of ttSlot.meta. Depths are kept in tenths of a ply.
+const (
+	metaDepthBits  = 16
+	metaTypShift   = 16
+	metaGenShift   = 18
+	metaUsed       = uint64(1) << 26
+	metaDepthScale = 10
+)
+
+func packMeta(depth float64, typ int, generation uint8) uint64 {
+	d := uint64(max(0, math.Round(depth*metaDepthScale))) & (1<<metaDepthBits - 1)
+	return d | uint64(typ&3)<<metaTypShift | uint64(generation)<<metaGenShift | metaUsed
+}
+
+func metaDepth(meta uint64) float64 {
+	return float64(meta&(1<<metaDepthBits-1)) / metaDepthScale
+}
+
+func metaGeneration(meta uint64) uint8 {
+	return uint8(meta >> metaGenShift)
+}
+
+// TTStats reports how full the table is.
+type TTStats struct {
+	Hashfull int // permille of the sampled slots filled in the current search
+	Buckets  int
+}
+
+// NewTransTable allocates a table of about mb megabytes.
+func NewTransTable(mb int) *TransTable {
+	tt := &TransTable{}
+	tt.Resize(mb)
+	return tt
+}
+
+// Resize reallocates the table at about mb megabytes, dropping its contents.
+// It must not be called during a search.
+func (tt *TransTable) Resize(mb int) {
+	n := mb * 1024 * 1024 / int(unsafe.Sizeof(ttBucket{}))
+	if n < 1 {
+		n = 1
+	}
+	tt.buckets = make([]ttBucket, n)
+	tt.generation = 0
+}
+
+// Clear empties the table. It must not be called during a search.
+func (tt *TransTable) Clear() {
+	clear(tt.buckets)
+	tt.generation = 0
+}
+
+// NewSearch ages every entry by one generation. Call it before each move's
+// search, before any thread starts.
+func (tt *TransTable) NewSearch() {
+	tt.generation++
+}
+
+func (tt *TransTable) bucket(key uint64) *ttBucket {
+	return &tt.buckets[key%uint64(len(tt.buckets))]
+}
+
+// load reads the slot and reports whether it holds a consistent entry for key.
+func (s *ttSlot) load(key uint64) (value, meta, move uint64, ok bool) {
+	value, meta, move = s.value.Load(), s.meta.Load(), s.move.Load()
+	ok = meta&metaUsed != 0 && s.check.Load()^value^meta^move == key
+	return value, meta, move, ok
+}
+
+func (s *ttSlot) store(key, value, meta, move uint64) {
+	s.value.Store(value)
+	s.meta.Store(meta)
+	s.move.Store(move)
+	s.check.Store(key ^ value ^ meta ^ move)
+}
+
+// Probe looks up the entry stored for the position bb, keyed by its hash.
+func (tt *TransTable) Probe(bb *checkers.BitBoard) (Entry, bool) {
+	bk := tt.bucket(bb.Hash)
+	for _, s := range [2]*ttSlot{&bk.deep, &bk.recent} {
+		if value, meta, move, ok := s.load(bb.Hash); ok {
+			return Entry{
+				Pos:   Position{Move: bb.UnpackMove(move), Value: math.Float64frombits(value)},
+				Depth: metaDepth(meta),
+				Typ:   int(meta>>metaTypShift) & 3,
+			}, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Store records e for the position bb. The depth-preferred slot takes it if it
+// is at least as deep as what is there, or if that entry is from an earlier
+// search or for another position; otherwise it goes in the always-replace slot.
+func (tt *TransTable) Store(bb *checkers.BitBoard, e Entry) {
+	key := bb.Hash
+	bk := tt.bucket(key)
+	value := math.Float64bits(e.Pos.Value)
+	meta := packMeta(e.Depth, e.Typ, tt.generation)
+	move := e.Pos.Move.Pack()
+
+	_, old, _, same := bk.deep.load(key)
+	switch {
+	case old&metaUsed == 0 || same || metaGeneration(old) != tt.generation || e.Depth >= metaDepth(old):
+		bk.deep.store(key, value, meta, move)
+	default:
+		bk.recent.store(key, value, meta, move)
+	}
+}
+
+// Stats returns how full the table is. Hashfull samples the first thousand slots.
+func (tt *TransTable) Stats() TTStats {
+	sample := min(len(tt.buckets), 500)
+	filled := 0
+	for i := 0; i < sample; i++ {
+		for _, s := range [2]*ttSlot{&tt.buckets[i].deep, &tt.buckets[i].recent} {
+			if meta := s.meta.Load(); meta&metaUsed != 0 && metaGeneration(meta) == tt.generation {
+				filled++
+			}
+		}
+	}
+	return TTStats{
+		Hashfull: filled * 1000 / (2 * sample),
+		Buckets:  len(tt.buckets),
+	}
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	"myproject/checkers"
+)
+
+// positions returns n different positions from a random game, each with a
+// move that can be played from it.
+func positions(t *testing.T, n int) ([]checkers.BitBoard, []checkers.Move) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	b := checkers.NewBoard()
+	var bbs []checkers.BitBoard
+	var moves []checkers.Move
+	for len(bbs) < n {
+		legal := b.GenerateAllMoves()
+		if len(legal) == 0 {
+			t.Fatal("the game ended too soon")
+		}
+		m := legal[r.Intn(len(legal))]
+		bbs = append(bbs, *b.BitBoard)
+		moves = append(moves, m)
+		m.MakeMove(b)
+	}
+	return bbs, moves
+}
+
+func TestTransTableStoreProbe(t *testing.T) {
+	tt := NewTransTable(1)
+	bbs, moves := positions(t, 2)
+	want := Entry{Pos: Position{Move: moves[0], Value: -1.25}, Depth: 6.5, Typ: LOWERBOUND}
+	tt.Store(&bbs[0], want)
+
+	got, ok := tt.Probe(&bbs[0])
+	if !ok {
+		t.Fatal("the entry stored is missing")
+	}
+	if !got.Pos.Move.Equals(want.Pos.Move) || got.Pos.Value != want.Pos.Value || got.Depth != want.Depth || got.Typ != want.Typ {
+		t.Fatalf("probe gave %+v, want %+v", got, want)
+	}
+	if _, ok := tt.Probe(&bbs[1]); ok {
+		t.Fatal("a position never stored was found")
+	}
+}
+
+// A table of one bucket puts every position in the same pair of slots.
+func TestTransTableReplacement(t *testing.T) {
+	tt := NewTransTable(0)
+	if s := tt.Stats(); s.Buckets != 1 || s.Hashfull != 0 {
+		t.Fatalf("empty table stats %+v", s)
+	}
+	bbs, moves := positions(t, 4)
+	entry := func(i int, depth float64) Entry {
+		return Entry{Pos: Position{Move: moves[i], Value: float64(i)}, Depth: depth}
+	}
+	depthOf := func(i int) float64 {
+		e, ok := tt.Probe(&bbs[i])
+		if !ok {
+			return -1
+		}
+		return e.Depth
+	}
+
+	tt.Store(&bbs[0], entry(0, 8))
+	if depthOf(1) != -1 {
+		t.Fatal("another position in the same bucket was found")
+	}
+	if s := tt.Stats(); s.Hashfull != 500 {
+		t.Fatalf("one slot of two filled gives hashfull %d", s.Hashfull)
+	}
+	tt.Store(&bbs[1], entry(1, 3))
+	tt.Store(&bbs[2], entry(2, 2))
+	if depthOf(0) != 8 || depthOf(1) != -1 || depthOf(2) != 2 {
+		t.Fatalf("shallower stores: depths %v %v %v, want the deep entry kept and the last in the other slot", depthOf(0), depthOf(1), depthOf(2))
+	}
+	if s := tt.Stats(); s.Hashfull != 1000 {
+		t.Fatalf("both slots filled gives hashfull %d", s.Hashfull)
+	}
+
+	// A deeper result for another position takes the deep slot
+	tt.Store(&bbs[3], entry(3, 9))
+	if depthOf(0) != -1 || depthOf(3) != 9 {
+		t.Fatalf("deeper store: depths %v %v", depthOf(0), depthOf(3))
+	}
+
+	// In the next search the old deep entry gives way to anything
+	tt.NewSearch()
+	if s := tt.Stats(); s.Hashfull != 0 {
+		t.Fatalf("entries from the last search count towards hashfull %d", s.Hashfull)
+	}
+	tt.Store(&bbs[0], entry(0, 1))
+	if depthOf(3) != -1 || depthOf(0) != 1 {
+		t.Fatalf("aged store: depths %v %v", depthOf(3), depthOf(0))
+	}
+
+	tt.Clear()
+	if depthOf(0) != -1 || depthOf(2) != -1 {
+		t.Fatal("Clear left entries behind")
+	}
+}
+
+// A slot with one word overwritten, as when another thread is half way
+// through storing to it, fails the key check.
+func TestTransTableTornSlot(t *testing.T) {
+	tt := NewTransTable(0)
+	bbs, moves := positions(t, 1)
+	tt.Store(&bbs[0], Entry{Pos: Position{Move: moves[0], Value: 0.5}, Depth: 4})
+	if _, ok := tt.Probe(&bbs[0]); !ok {
+		t.Fatal("the entry stored is missing")
+	}
+	tt.buckets[0].deep.value.Store(12345)
+	if _, ok := tt.Probe(&bbs[0]); ok {
+		t.Fatal("a slot written by halves passed the key check")
+	}
+}
@@ -1,35 +1,261 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"myproject/checkers"
+	"myproject/engine"
+	"myproject/engine/endgame"
+	"myproject/engine/negascout"
+	"myproject/engine/players"
+	"myproject/pdn"
+)
+
+// gameFile is where S saves the current game and L loads it from
+const gameFile = "game.pdn"
+
+const (
+	panelHeight = 60 // strip under the board showing the last search
+	panelLines  = 3  // lines ranked in analysis mode
+	lineHeight  = 14
+	panelChars  = boardPixelSize / 6 // the debug font is 6 pixels wide
 )
 
 type Game struct {
-	board *Board
+	board *BoardView
+	red   engine.Engine
+	black engine.Engine
+	hint  engine.Engine // plays for a human who presses R
+
+	search *search // the move being worked out, if any
+	paused bool    // the bots wait until space is pressed
+	ponder bool    // bots think on the human's time; toggled with P
+
+	analysis bool               // bots rank their best moves; toggled with A
+	tables   *endgame.Distances // endgame distance tables, if loaded
+	perfect  bool               // positions the tables hold are played from them; toggled with E
+	lastName string             // who made the last search
+	lastInfo engine.SearchInfo  // what the last search found
 }
 
 // Initialize the game and board
-func NewGame() *Game {
+func NewGame(red, black engine.Engine) *Game {
 	return &Game{
-		board: NewBoard(),
+		board: NewBoardView(),
+		red:   red,
+		black: black,
+		hint:  negascout.NewBot(),
+	}
+}
+
+// toMove returns the player whose turn it is
+func (g *Game) toMove() engine.Engine {
+	if g.board.board.BitBoard.IsRedTurn {
+		return g.red
+	}
+	return g.black
+}
+
+// play makes move on the board and clears the selection
+func (g *Game) play(move checkers.Move) {
+	b := g.board.board
+	move.MakeMove(b)
+	b.PlyCount++
+	g.board.selectedPiece = nil
+	g.board.possibleMoves = nil
+}
+
+// perfectMove returns the move the endgame tables would play, if perfect
+// endgame play is on and they hold the position
+func (g *Game) perfectMove() (checkers.Move, bool) {
+	if !g.perfect {
+		return checkers.Move{}, false
+	}
+	m, _, _, ok := g.tables.BestMove(g.board.board)
+	return m, ok
+}
+
+// think starts p working out a move in the background
+func (g *Game) think(p engine.Engine) {
+	limits := engine.DefaultLimits
+	if g.analysis {
+		limits.MultiPV = panelLines
 	}
+	g.search = startSearch(p, g.board.board, limits)
+}
+
+// collect plays the background search's move once it is ready
+func (g *Game) collect() {
+	r, ok := g.search.poll()
+	if !ok {
+		return
+	}
+	s := g.search
+	g.search = nil
+	switch {
+	case s.discard:
+	case r.err != nil:
+		log.Println(s.player.Name()+":", r.err)
+	default:
+		g.lastName, g.lastInfo = s.player.Name(), s.player.Info()
+		g.play(r.move)
+		if s.player != g.hint {
+			g.startPondering(s.player)
+		}
+	}
+}
+
+// startPondering has p think on the human's time, if pondering is on and a
+// human is to reply
+func (g *Game) startPondering(p engine.Engine) {
+	_, human := g.toMove().(*engine.Human)
+	if pp, ok := p.(engine.Ponderer); ok && g.ponder && human && !g.board.board.Result().IsOver() {
+		pp.Ponder(g.board.board)
+	}
+}
+
+// stopPondering stops both players pondering
+func (g *Game) stopPondering() {
+	for _, p := range []engine.Engine{g.red, g.black} {
+		if pp, ok := p.(engine.Ponderer); ok {
+			pp.StopPondering()
+		}
+	}
+}
+
+// stopThinking abandons any background search and waits for it to wind up,
+// so its player is free to be used again
+func (g *Game) stopThinking() {
+	if g.search == nil {
+		return
+	}
+	g.search.abandon()
+	<-g.search.done
+	g.search = nil
 }
 
 // Layout specifies the window size
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return 400, 400
+	return boardPixelSize, boardPixelSize + panelHeight
 }
 
 // Update processes input and updates the game state
 func (g *Game) Update() error {
-	// Pass mouse events to the board
-	g.board.Update()
-	if ebiten.IsKeyPressed(ebiten.KeyR) && g.board.bitBoard.isRedTurn {
-		bot1.think(g.board)
-		println("-")
+	if g.search != nil {
+		g.collect()
+	}
+	// Space makes a thinking bot move at once, or wakes paused bots;
+	// escape stops the search and pauses the bots
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if g.search != nil {
+			g.search.moveNow()
+		} else {
+			g.paused = false
+		}
 	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && g.search != nil {
+		g.search.abandon()
+		g.paused = true
+	}
+
+	// Pass mouse events to the board; clicks are ignored while a search runs
+	_, human := g.toMove().(*engine.Human)
+	if move, ok := g.board.Update(human && g.search == nil); ok {
+		g.play(move)
+	} else if !g.board.result.IsOver() && g.search == nil {
+		// Bots and hints need no search where the tables know the best move
+		if !human && !g.paused {
+			if m, ok := g.perfectMove(); ok {
+				g.play(m)
+			} else {
+				g.think(g.toMove())
+			}
+		} else if human && inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			if m, ok := g.perfectMove(); ok {
+				g.play(m)
+			} else {
+				g.think(g.hint)
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		if err := g.save(); err != nil {
+			log.Println("save:", err)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		g.ponder = !g.ponder
+		if !g.ponder {
+			g.stopPondering()
+		}
+		log.Println("pondering:", g.ponder)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		g.analysis = !g.analysis
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		if g.tables == nil {
+			log.Println("perfect endgame: no tables loaded, start with -dtw FILE")
+		} else {
+			g.perfect = !g.perfect
+			log.Println("perfect endgame:", g.perfect)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		log.Println("FEN:", g.board.board.BitBoard.FEN())
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		if err := g.load(); err != nil {
+			log.Println("load:", err)
+		}
+	}
+	return nil
+}
+
+// save writes the game played so far to gameFile
+func (g *Game) save() error {
+	record := pdn.FromBoard(g.board.board)
+	record.SetTag("Event", "Casual game")
+	// Red moves first, which PDN calls Black
+	record.SetTag("Black", g.red.Name())
+	record.SetTag("White", g.black.Name())
+	return os.WriteFile(gameFile, []byte(record.String()), 0o644)
+}
+
+// load replaces the board with the first game in gameFile
+func (g *Game) load() error {
+	f, err := os.Open(gameFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	games, err := pdn.Read(f)
+	if err != nil {
+		return err
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("%s has no games", gameFile)
+	}
+	b, err := games[0].Replay()
+	if err != nil {
+		return err
+	}
+	g.stopThinking()
+	g.board.board = b
+	g.board.selectedPiece = nil
+	g.board.possibleMoves = nil
+	g.red.Reset()
+	g.black.Reset()
 	return nil
 }
 
@@ -37,14 +263,104 @@ func (g *Game) Update() error {
 func (g *Game) Draw(screen *ebiten.Image) {
 	// Draw the board
 	g.board.Draw(screen)
+	switch {
+	case g.search != nil && !g.search.discard:
+		elapsed := time.Since(g.search.started).Seconds()
+		ebitenutil.DebugPrint(screen, fmt.Sprintf("%s thinking... %.1fs", g.search.player.Name(), elapsed))
+	case g.paused:
+		ebitenutil.DebugPrint(screen, "paused, space to resume")
+	case g.perfect:
+		if v, d, ok := g.tables.Probe(g.board.board.BitBoard); ok && !g.board.result.IsOver() {
+			side := "black"
+			if g.board.board.BitBoard.IsRedTurn {
+				side = "red"
+			}
+			text := "perfect endgame: drawn"
+			if v != endgame.Draw {
+				text = fmt.Sprintf("perfect endgame: %s to move, %s in %d", side, v, d)
+			}
+			ebitenutil.DebugPrint(screen, text)
+		}
+	}
+	g.drawPanel(screen)
+}
+
+// drawPanel shows the running or last search under the board: its depth and
+// speed and the lines it found, several of them in analysis mode
+func (g *Game) drawPanel(screen *ebiten.Image) {
+	name, info := g.lastName, g.lastInfo
+	// While a bot thinks, show how far it has got
+	if g.search != nil && !g.search.discard {
+		if r, ok := g.search.player.(engine.Reporting); ok {
+			name, info = g.search.player.Name(), r.Current()
+		}
+	}
+	header := "A: analysis off"
+	if g.analysis {
+		header = "A: analysis on"
+	}
+	if name != "" {
+		header = fmt.Sprintf("%s  depth %d/%d  %dk nps  %s", name, info.Depth, info.SelDepth, info.NPS/1000, header)
+	}
+	ebitenutil.DebugPrintAt(screen, header, 2, boardPixelSize)
+	for i, l := range info.Lines {
+		text := fmt.Sprintf("%d. %s", i+1, l)
+		if len(text) > panelChars {
+			text = text[:panelChars]
+		}
+		ebitenutil.DebugPrintAt(screen, text, 2, boardPixelSize+(i+1)*lineHeight)
+	}
 }
 
 func main() {
+	choices := strings.Join(players.Names, ", ")
+	redName := flag.String("red", "human", "player for red, who moves first: "+choices)
+	blackName := flag.String("black", "negascout", "player for black: "+choices)
+	threads := flag.Int("threads", 1, "search threads for engines that support them")
+	egdb := flag.String("egdb", "", "endgame database for engines that can consult one")
+	dtw := flag.String("dtw", "", "endgame distance tables, for engines and for perfect endgame play (E)")
+	bookFile := flag.String("book", "", "opening book for engines to play from")
+	flag.Parse()
+	red, err := players.New(*redName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	black, err := players.New(*blackName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, p := range []engine.Engine{red, black} {
+		if t, ok := p.(engine.Threaded); ok {
+			t.SetThreads(*threads)
+		}
+	}
+
 	// Create a new game instance
-	game := NewGame()
+	game := NewGame(red, black)
+	if *dtw != "" {
+		if game.tables, err = endgame.OpenDistances(*dtw); err != nil {
+			log.Fatal(err)
+		}
+		game.perfect = true
+	}
+	for _, p := range []engine.Engine{red, black, game.hint} {
+		c, ok := p.(engine.Configurable)
+		if !ok {
+			continue
+		}
+		for _, f := range [][2]string{{"egdb", *egdb}, {"dtw", *dtw}, {"book", *bookFile}} {
+			if f[1] == "" {
+				continue
+			}
+			if err := c.SetOption(f[0], f[1]); err != nil && !errors.Is(err, engine.ErrUnknownOption) {
+				log.Fatal(err)
+			}
+		}
+	}
 
 	// Set the window title and start the game
-	ebiten.SetWindowSize(400, 400)
+	ebiten.SetWindowSize(boardPixelSize, boardPixelSize+panelHeight)
 	ebiten.SetWindowTitle("Ebiten 8x8 Board")
 
 	// Run the game
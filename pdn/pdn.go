@@ -0,0 +1,159 @@
+// Package pdn reads and writes games in Portable Draughts Notation.
+//
+// Squares are written with the standard 1-32 numbering from checkers.SquareNumber.
+// Red moves first and is therefore "Black" in PDN terms, and results are written
+// from Black's side: "1-0" is a red win and "0-1" a win for the other side.
+package pdn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"myproject/checkers"
+)
+
+// GameTypeEnglish is the PDN GameType tag value for English draughts.
+const GameTypeEnglish = "21"
+
+type Tag struct {
+	Name, Value string
+}
+
+// Move is one turn as written in PDN. A multi-jump is a single Move listing
+// every landing square, or just the first and last when that is unambiguous.
+type Move struct {
+	Squares    []int
+	Capture    bool
+	Comment    string
+	Variations [][]Move // alternatives to this move, played from the position before it
+}
+
+// Game is a tag section followed by the moves of one game.
+type Game struct {
+	Tags    []Tag
+	Comment string // comment written before the first move
+	Moves   []Move
+	Result  string
+}
+
+// Tag returns the value of the named tag, or "" if the game does not have it.
+func (g *Game) Tag(name string) string {
+	for _, t := range g.Tags {
+		if t.Name == name {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// SetTag replaces the named tag, or adds it to the end of the tag section.
+func (g *Game) SetTag(name, value string) {
+	for i, t := range g.Tags {
+		if t.Name == name {
+			g.Tags[i].Value = value
+			return
+		}
+	}
+	g.Tags = append(g.Tags, Tag{name, value})
+}
+
+func (m Move) String() string {
+	sep := "-"
+	if m.Capture {
+		sep = "x"
+	}
+	parts := make([]string, len(m.Squares))
+	for i, sq := range m.Squares {
+		parts[i] = strconv.Itoa(sq)
+	}
+	return strings.Join(parts, sep)
+}
+
+// ResultString converts a board result to a PDN result token.
+func ResultString(r checkers.Result) string {
+	switch r.Status {
+	case checkers.RedWins:
+		return "1-0"
+	case checkers.BlackWins:
+		return "0-1"
+	case checkers.Draw:
+		return "1/2-1/2"
+	}
+	return "*"
+}
+
+// Replay plays the game through MakeMove on a new board, checking that every
+// move, including those in variations, is legal. The board is left at the end
+// of the main line.
+func (g *Game) Replay() (*checkers.Board, error) {
+	b, err := g.startBoard()
+	if err != nil {
+		return nil, err
+	}
+	if err := replayMoves(b, g.Moves); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (g *Game) startBoard() (*checkers.Board, error) {
+	if gt := g.Tag("GameType"); gt != "" && !strings.HasPrefix(gt, GameTypeEnglish) {
+		return nil, fmt.Errorf("pdn: unsupported GameType %q", gt)
+	}
+	if fen := g.Tag("FEN"); fen != "" {
+		return checkers.NewBoardFromFEN(fen)
+	}
+	return checkers.NewBoard(), nil
+}
+
+func replayMoves(b *checkers.Board, moves []Move) error {
+	for i, m := range moves {
+		for _, v := range m.Variations {
+			b.Save()
+			err := replayMoves(b, v)
+			b.Load()
+			if err != nil {
+				return fmt.Errorf("variation of move %d (%s): %w", i+1, m, err)
+			}
+		}
+		move, err := resolve(b, m)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+		move.MakeMove(b)
+	}
+	return nil
+}
+
+// resolve finds the legal move that the notation describes.
+func resolve(b *checkers.Board, m Move) (checkers.Move, error) {
+	if len(m.Squares) < 2 {
+		return checkers.Move{}, fmt.Errorf("pdn: move %q needs at least two squares", m)
+	}
+	move, err := b.FindMove(m.Squares)
+	if err != nil {
+		return checkers.Move{}, fmt.Errorf("pdn: %w %q", err, m)
+	}
+	return move, nil
+}
+
+// FromBoard records the game played on b. The Result tag is filled in from the board; the caller can
+// replace the Event tag and add player names.
+func FromBoard(b *checkers.Board) *Game {
+	g := &Game{Tags: []Tag{{"Event", "?"}, {"Black", "?"}, {"White", "?"}, {"Result", "*"}, {"GameType", GameTypeEnglish}}}
+
+	start := b.StartPosition()
+	if start != checkers.NewBoard().StartPosition() {
+		g.SetTag("SetUp", "1")
+		g.SetTag("FEN", start.FEN())
+	}
+
+	for _, move := range b.Moves() {
+		g.Moves = append(g.Moves, Move{Squares: move.Squares(), Capture: move.IsJump})
+	}
+
+	g.Result = ResultString(b.Result())
+	g.SetTag("Result", g.Result)
+	return g
+}
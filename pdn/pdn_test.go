@@ -0,0 +1,135 @@
+package pdn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"myproject/checkers"
+)
+
+func TestRoundTrip(t *testing.T) {
+	b := checkers.NewBoard()
+	for _, text := range []string{"11-15", "22-18", "15x22", "25x18"} {
+		m, err := ParseMove(b, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.MakeMove(b)
+		b.PlyCount++
+	}
+	g := FromBoard(b)
+	g.SetTag("Event", `The "Open" [final]`)
+	g.SetTag("Site", `C:\games\]`)
+	g.SetTag("Black", "Ann")
+	g.Moves[1].Comment = "the usual reply"
+
+	var buf bytes.Buffer
+	if err := Write(&buf, g, g); err != nil {
+		t.Fatal(err)
+	}
+	games, err := Parse(buf.String())
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, buf.String())
+	}
+	if len(games) != 2 {
+		t.Fatalf("read %d games, want 2", len(games))
+	}
+	for _, got := range games {
+		if !reflect.DeepEqual(got, g) {
+			t.Fatalf("read back\n%s\nwant\n%s", got, g)
+		}
+		end, err := got.Replay()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *end.BitBoard != *b.BitBoard {
+			t.Fatalf("replay ends at\n%s\nwant\n%s", end.BitBoard, b.BitBoard)
+		}
+	}
+}
+
+func TestCommentsAndVariations(t *testing.T) {
+	games, err := Parse(`[Event "?"]
+{before the game} 1. 11-15 {the favourite} 23-19 (22-18 15x22 25x18 (26x17)) 2. 8-11 22-17 *`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := games[0]
+	if g.Comment != "before the game" || g.Moves[0].Comment != "the favourite" {
+		t.Fatalf("comments %q and %q", g.Comment, g.Moves[0].Comment)
+	}
+	if len(g.Moves) != 4 || g.Result != "*" {
+		t.Fatalf("main line %v %s, want four moves", g.Moves, g.Result)
+	}
+	vars := g.Moves[1].Variations
+	if len(vars) != 1 || len(vars[0]) != 3 || !vars[0][1].Capture {
+		t.Fatalf("variations of 23-19: %v", vars)
+	}
+	if inner := vars[0][2].Variations; len(inner) != 1 || inner[0][0].String() != "26x17" {
+		t.Fatalf("variations of 25x18: %v", inner)
+	}
+	if _, err := g.Replay(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"1. 11-15 (23-19", "1. 11-15 23-19)", "(11-15)", "{open"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("%q parsed without an error", text)
+		}
+	}
+}
+
+func TestSetUp(t *testing.T) {
+	games, err := Parse(`[SetUp "1"]
+[FEN "B:W14,23:B9"]
+1. 9x18x27 1-0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := games[0]
+	if m := g.Moves[0]; !m.Capture || !reflect.DeepEqual(m.Squares, []int{9, 18, 27}) {
+		t.Fatalf("read %v, want the chain 9x18x27", m)
+	}
+	b, err := g.Replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Result().Status != checkers.RedWins {
+		t.Fatalf("after the double jump the result is %v", b.Result())
+	}
+
+	// The writer numbers a game that starts with White to move from "1..."
+	g.SetTag("FEN", "W:W14,23:B9")
+	g.Moves = []Move{{Squares: []int{23, 19}}}
+	if text := g.String(); !strings.Contains(text, "1... 23-19") {
+		t.Fatalf("wrote\n%s", text)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	for _, tc := range []struct {
+		text, err string
+	}{
+		{"1. 11-17 *", "illegal"},
+		{"1. 11-15 23-19 (22-18 9-13) *", "illegal"},
+		{"1. 11-15 22-18 2. 9-13 *", "illegal"}, // 15x22 is compulsory
+		{"[FEN \"B:W9,10,17,18,27:BK13\"]\n1. 13x13 *", "ambiguous"},
+		{"[GameType \"20\"]\n1. 32-28 *", "GameType"},
+	} {
+		games, err := Parse(tc.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := games[0].Replay(); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("replaying %q: got %v, want an error mentioning %q", tc.text, err, tc.err)
+		}
+	}
+
+	// The full path picks one of the two ways round
+	games, _ := Parse("[FEN \"B:W9,10,17,18,27:BK13\"]\n1. 13x6x15x22x13 *")
+	if _, err := games[0].Replay(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,293 @@
+package pdn
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"myproject/checkers"
+)
+
+var resultTokens = map[string]bool{
+	"1-0": true, "0-1": true, "1/2-1/2": true,
+	"2-0": true, "0-2": true, "1-1": true,
+	"*": true,
+}
+
+// Read parses every game in a PDN file.
+func Read(r io.Reader) ([]*Game, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
+
+// Parse parses every game in a PDN string. Moves are only checked for syntax;
+// use Game.Replay to check that they are legal.
+func Parse(text string) ([]*Game, error) {
+	p := &parser{text: []rune(text)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.games, nil
+}
+
+type parser struct {
+	text  []rune
+	pos   int
+	games []*Game
+	game  *Game
+	lines []*[]Move // the main line followed by any open variations
+}
+
+func (p *parser) parse() error {
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.text) {
+			break
+		}
+		c := p.text[p.pos]
+		switch c {
+		case '[':
+			if err := p.readTag(); err != nil {
+				return err
+			}
+		case '{':
+			comment, err := p.readUntil('}')
+			if err != nil {
+				return err
+			}
+			p.addComment(comment)
+		case '(':
+			p.pos++
+			if err := p.openVariation(); err != nil {
+				return err
+			}
+		case ')':
+			p.pos++
+			if len(p.lines) < 2 {
+				return fmt.Errorf("pdn: unmatched ')' at offset %d", p.pos-1)
+			}
+			p.lines = p.lines[:len(p.lines)-1]
+		default:
+			if err := p.readWord(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(p.lines) > 1 {
+		return fmt.Errorf("pdn: unterminated variation")
+	}
+	p.finishGame()
+	return nil
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.text) && unicode.IsSpace(p.text[p.pos]) {
+		p.pos++
+	}
+}
+
+// readUntil consumes a delimited section and returns what was inside it.
+func (p *parser) readUntil(end rune) (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.text) && p.text[p.pos] != end {
+		p.pos++
+	}
+	if p.pos >= len(p.text) {
+		return "", fmt.Errorf("pdn: missing %q for section at offset %d", end, start)
+	}
+	p.pos++
+	return strings.TrimSpace(string(p.text[start+1 : p.pos-1])), nil
+}
+
+// current returns the game being read, starting a new one if needed.
+func (p *parser) current() *Game {
+	if p.game == nil {
+		p.game = &Game{}
+		p.lines = []*[]Move{&p.game.Moves}
+	}
+	return p.game
+}
+
+func (p *parser) finishGame() {
+	if p.game != nil {
+		p.games = append(p.games, p.game)
+	}
+	p.game = nil
+	p.lines = nil
+}
+
+func (p *parser) readTag() error {
+	// A tag after the move text belongs to the next game
+	if p.game != nil && (len(p.game.Moves) > 0 || p.game.Result != "") {
+		p.finishGame()
+	}
+	start := p.pos
+	p.pos++
+	p.skipSpace()
+	nameStart := p.pos
+	for p.pos < len(p.text) && !unicode.IsSpace(p.text[p.pos]) && p.text[p.pos] != '"' && p.text[p.pos] != ']' {
+		p.pos++
+	}
+	name := string(p.text[nameStart:p.pos])
+	p.skipSpace()
+	if name == "" || p.pos >= len(p.text) || p.text[p.pos] != '"' {
+		return fmt.Errorf("pdn: malformed tag at offset %d", start)
+	}
+	value, err := p.readQuoted()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.pos >= len(p.text) || p.text[p.pos] != ']' {
+		return fmt.Errorf("pdn: missing ']' for tag %s at offset %d", name, start)
+	}
+	p.pos++
+	p.current().SetTag(name, value)
+	return nil
+}
+
+// readQuoted consumes a quoted string, in which a backslash escapes the
+// character after it, and returns its value. The writer quotes values with
+// strconv.Quote, so its escapes are decoded too; a string Go cannot unquote
+// falls back to the PDN rule of just dropping each escaping backslash.
+func (p *parser) readQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.text) && p.text[p.pos] != '"' {
+		if p.text[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.text) {
+		return "", fmt.Errorf("pdn: unterminated string at offset %d", start)
+	}
+	p.pos++
+	quoted := string(p.text[start:p.pos])
+	if s, err := strconv.Unquote(quoted); err == nil {
+		return s, nil
+	}
+	var sb strings.Builder
+	inner := []rune(quoted[1 : len(quoted)-1])
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' && i+1 < len(inner) {
+			i++
+		}
+		sb.WriteRune(inner[i])
+	}
+	return sb.String(), nil
+}
+
+func (p *parser) addComment(comment string) {
+	g := p.current()
+	moves := *p.lines[len(p.lines)-1]
+	if len(moves) == 0 {
+		if len(p.lines) == 1 {
+			g.Comment = joinComment(g.Comment, comment)
+		}
+		return
+	}
+	last := &moves[len(moves)-1]
+	last.Comment = joinComment(last.Comment, comment)
+}
+
+func joinComment(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
+
+func (p *parser) openVariation() error {
+	if p.game == nil {
+		return fmt.Errorf("pdn: variation before any move at offset %d", p.pos-1)
+	}
+	moves := *p.lines[len(p.lines)-1]
+	if len(moves) == 0 {
+		return fmt.Errorf("pdn: variation before any move at offset %d", p.pos-1)
+	}
+	last := &moves[len(moves)-1]
+	last.Variations = append(last.Variations, nil)
+	p.lines = append(p.lines, &last.Variations[len(last.Variations)-1])
+	return nil
+}
+
+func (p *parser) readWord() error {
+	start := p.pos
+	for p.pos < len(p.text) && !unicode.IsSpace(p.text[p.pos]) && !strings.ContainsRune("[]{}()", p.text[p.pos]) {
+		p.pos++
+	}
+	word := string(p.text[start:p.pos])
+
+	if resultTokens[word] {
+		if p.game == nil || len(p.lines) == 1 {
+			p.current().Result = word
+		}
+		return nil
+	}
+	if strings.HasPrefix(word, "$") {
+		return nil // numeric annotation glyph
+	}
+
+	// Strip a move number, which may be glued to the move as in "1.11-15"
+	i := 0
+	for i < len(word) && word[i] >= '0' && word[i] <= '9' {
+		i++
+	}
+	if i < len(word) && word[i] == '.' {
+		for i < len(word) && word[i] == '.' {
+			i++
+		}
+		word = word[i:]
+		if word == "" {
+			return nil
+		}
+	}
+
+	move, err := parseMove(word)
+	if err != nil {
+		return fmt.Errorf("%w at offset %d", err, start)
+	}
+	p.current()
+	line := p.lines[len(p.lines)-1]
+	*line = append(*line, move)
+	return nil
+}
+
+func parseMove(word string) (Move, error) {
+	word = strings.TrimRight(word, "!?")
+	var m Move
+	sep := "-"
+	if strings.ContainsAny(word, "x:") {
+		m.Capture = true
+		sep = "x"
+		word = strings.ReplaceAll(word, ":", "x")
+	}
+	for _, part := range strings.Split(word, sep) {
+		sq, err := strconv.Atoi(part)
+		if err != nil || sq < 1 || sq > 32 {
+			return Move{}, fmt.Errorf("pdn: bad move %q", word)
+		}
+		m.Squares = append(m.Squares, sq)
+	}
+	if len(m.Squares) < 2 {
+		return Move{}, fmt.Errorf("pdn: bad move %q", word)
+	}
+	return m, nil
+}
+
+// ParseMove reads a single move in square notation, such as "11-15" or
+// "9x18x27", and returns the legal move on b that it describes.
+func ParseMove(b *checkers.Board, text string) (checkers.Move, error) {
+	m, err := parseMove(strings.TrimSpace(text))
+	if err != nil {
+		return checkers.Move{}, err
+	}
+	return resolve(b, m)
+}
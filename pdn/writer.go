@@ -0,0 +1,116 @@
+package pdn
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+const lineWidth = 79
+
+// Write writes the games in PDN, separated by blank lines.
+func Write(w io.Writer, games ...*Game) error {
+	for i, g := range games {
+		text := g.String()
+		if i > 0 {
+			text = "\n" + text
+		}
+		if _, err := io.WriteString(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String formats the game as PDN text ending in a newline.
+func (g *Game) String() string {
+	var sb strings.Builder
+	result := g.Result
+	if result == "" {
+		result = g.Tag("Result")
+	}
+	if result == "" {
+		result = "*"
+	}
+	for _, t := range g.Tags {
+		sb.WriteString("[" + t.Name + " " + strconv.Quote(t.Value) + "]\n")
+	}
+	if len(g.Tags) > 0 {
+		sb.WriteString("\n")
+	}
+
+	mw := &moveWriter{}
+	if g.Comment != "" {
+		mw.word("{" + g.Comment + "}")
+	}
+	mw.moves(g.Moves, 1, g.firstMoverStarts())
+	mw.word(result)
+	sb.WriteString(mw.String())
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// firstMoverStarts reports whether the first move of the game is Black's (red's).
+func (g *Game) firstMoverStarts() bool {
+	b, err := g.startBoard()
+	if err != nil {
+		return true
+	}
+	return b.BitBoard.IsRedTurn
+}
+
+// moveWriter lays out move text, wrapping lines and keeping track of when
+// a move number has to be repeated for White.
+type moveWriter struct {
+	sb        strings.Builder
+	lineLen   int
+	glue      bool // write the next word without a space, as after "("
+	needBlack bool // the next White move must be preceded by "N..."
+}
+
+func (mw *moveWriter) word(w string) {
+	if mw.lineLen > 0 && !mw.glue && mw.lineLen+1+len(w) > lineWidth {
+		mw.sb.WriteString("\n")
+		mw.lineLen = 0
+	} else if mw.lineLen > 0 && !mw.glue {
+		mw.sb.WriteString(" ")
+		mw.lineLen++
+	}
+	mw.glue = false
+	mw.sb.WriteString(w)
+	mw.lineLen += len(w)
+}
+
+func (mw *moveWriter) moves(moves []Move, number int, blackToMove bool) {
+	mw.needBlack = true
+	for _, m := range moves {
+		if blackToMove {
+			mw.word(strconv.Itoa(number) + ". " + m.String())
+		} else if mw.needBlack {
+			mw.word(strconv.Itoa(number) + "... " + m.String())
+		} else {
+			mw.word(m.String())
+		}
+		mw.needBlack = false
+		if m.Comment != "" {
+			mw.word("{" + m.Comment + "}")
+			mw.needBlack = true
+		}
+		for _, v := range m.Variations {
+			mw.word("(")
+			mw.glue = true
+			mw.moves(v, number, blackToMove)
+			mw.glue = true
+			mw.word(")")
+			mw.needBlack = true
+		}
+		if !blackToMove {
+			number++
+		}
+		blackToMove = !blackToMove
+	}
+}
+
+func (mw *moveWriter) String() string {
+	return mw.sb.String()
+}
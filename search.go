@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"myproject/checkers"
+	"myproject/engine"
+)
+
+// search is a player working out a move in the background, on its own copy
+// of the board so the window can keep drawing the real one.
+type search struct {
+	player  engine.Engine
+	started time.Time
+	cancel  context.CancelFunc
+	done    chan searchResult
+	discard bool // the result is not wanted any more
+}
+
+type searchResult struct {
+	move checkers.Move
+	err  error
+}
+
+// startSearch asks p for a move on a copy of b.
+func startSearch(p engine.Engine, b *checkers.Board, limits engine.SearchLimits) *search {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &search{
+		player:  p,
+		started: time.Now(),
+		cancel:  cancel,
+		done:    make(chan searchResult, 1),
+	}
+	board := b.Clone()
+	go func() {
+		m, err := p.ChooseMove(ctx, board, limits)
+		s.done <- searchResult{m, err}
+	}()
+	return s
+}
+
+// moveNow stops the search; it answers with the best move found so far.
+func (s *search) moveNow() {
+	s.cancel()
+}
+
+// abandon stops the search and throws its answer away.
+func (s *search) abandon() {
+	s.discard = true
+	s.cancel()
+}
+
+// poll returns the search's answer once it has one, without waiting.
+func (s *search) poll() (searchResult, bool) {
+	select {
+	case r := <-s.done:
+		s.cancel()
+		return r, true
+	default:
+		return searchResult{}, false
+	}
+}